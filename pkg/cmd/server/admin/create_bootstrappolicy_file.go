@@ -78,13 +78,27 @@ func (o CreateBootstrapPolicyFileOptions) CreateBootstrapPolicyFile() error {
 		return err
 	}
 
+	buffer, err := BootstrapPolicyTemplateJSON(o.OpenShiftSharedResourcesNamespace)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(o.File, buffer, 0644)
+}
+
+// NewBootstrapPolicyTemplate builds the same in-memory bootstrap policy template
+// CreateBootstrapPolicyFile writes to disk - every cluster role, cluster role binding, and (scoped to
+// openShiftSharedResourcesNamespace) openshift role and role binding - without touching the filesystem. It's
+// the generator callers without a policy file on disk (for example a containerized master falling back to an
+// embedded default) build from directly, instead of writing a file only to immediately read it back.
+func NewBootstrapPolicyTemplate(openShiftSharedResourcesNamespace string) (*templateapi.Template, error) {
 	policyTemplate := &templateapi.Template{}
 
 	clusterRoles := bootstrappolicy.GetBootstrapClusterRoles()
 	for i := range clusterRoles {
 		versionedObject, err := kapi.Scheme.ConvertToVersion(&clusterRoles[i], latest.Version)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		policyTemplate.Objects = append(policyTemplate.Objects, versionedObject)
 	}
@@ -93,40 +107,47 @@ func (o CreateBootstrapPolicyFileOptions) CreateBootstrapPolicyFile() error {
 	for i := range clusterRoleBindings {
 		versionedObject, err := kapi.Scheme.ConvertToVersion(&clusterRoleBindings[i], latest.Version)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		policyTemplate.Objects = append(policyTemplate.Objects, versionedObject)
 	}
 
-	openshiftRoles := bootstrappolicy.GetBootstrapOpenshiftRoles(o.OpenShiftSharedResourcesNamespace)
+	openshiftRoles := bootstrappolicy.GetBootstrapOpenshiftRoles(openShiftSharedResourcesNamespace)
 	for i := range openshiftRoles {
 		versionedObject, err := kapi.Scheme.ConvertToVersion(&openshiftRoles[i], latest.Version)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		policyTemplate.Objects = append(policyTemplate.Objects, versionedObject)
 	}
 
-	openshiftRoleBindings := bootstrappolicy.GetBootstrapOpenshiftRoleBindings(o.OpenShiftSharedResourcesNamespace)
+	openshiftRoleBindings := bootstrappolicy.GetBootstrapOpenshiftRoleBindings(openShiftSharedResourcesNamespace)
 	for i := range openshiftRoleBindings {
 		versionedObject, err := kapi.Scheme.ConvertToVersion(&openshiftRoleBindings[i], latest.Version)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		policyTemplate.Objects = append(policyTemplate.Objects, versionedObject)
 	}
 
+	return policyTemplate, nil
+}
+
+// BootstrapPolicyTemplateJSON renders NewBootstrapPolicyTemplate's output as the same JSON bytes
+// CreateBootstrapPolicyFile writes to disk, for a caller that wants the embedded default bootstrap policy
+// without a file of its own to point at (see NewBootstrapPolicyTemplate).
+func BootstrapPolicyTemplateJSON(openShiftSharedResourcesNamespace string) ([]byte, error) {
+	policyTemplate, err := NewBootstrapPolicyTemplate(openShiftSharedResourcesNamespace)
+	if err != nil {
+		return nil, err
+	}
+
 	versionedPolicyTemplate, err := kapi.Scheme.ConvertToVersion(policyTemplate, latest.Version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	buffer := &bytes.Buffer{}
 	(&kprinters.JSONPrinter{}).PrintObj(versionedPolicyTemplate, buffer)
-
-	if err := ioutil.WriteFile(o.File, buffer.Bytes(), 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return buffer.Bytes(), nil
 }
@@ -5,6 +5,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	kapi "k8s.io/kubernetes/pkg/api"
 )
 
 // A new entry shall be added to FeatureAliases for every change to following values.
@@ -586,13 +587,421 @@ type PolicyConfig struct {
 	// OpenShiftSharedResourcesNamespace is the namespace where shared OpenShift resources live (like shared templates)
 	OpenShiftSharedResourcesNamespace string
 
+	// AdditionalSharedResourcesNamespaces is a list of additional namespaces that shared OpenShift resources
+	// should be replicated to alongside OpenShiftSharedResourcesNamespace, which remains authoritative for the
+	// primary namespace. Entries must not duplicate each other, OpenShiftSharedResourcesNamespace, or
+	// OpenShiftInfrastructureNamespace.
+	AdditionalSharedResourcesNamespaces []string
+
 	// OpenShiftInfrastructureNamespace is the namespace where OpenShift infrastructure resources live (like controller service accounts)
 	OpenShiftInfrastructureNamespace string
 
+	// ServiceAccountRolesInitializedAnnotation overrides the annotation key used to record that a
+	// namespace's bootstrap service account role bindings have been applied. Defaults to
+	// "openshift.io/sa.initialized-roles" when empty. Downstream distributions that rebrand the annotation
+	// domain, or tests that need isolation from other namespaces sharing the default key, can set this.
+	ServiceAccountRolesInitializedAnnotation string
+
+	// AlwaysReconcileClusterRoles lists additional cluster role names, beyond the built-in discovery role,
+	// that ensureComponentAuthorizationRules reconciles on every master start. Entries must name a known
+	// bootstrap cluster role; unrecognized names are logged and ignored. Be judicious about what's placed
+	// here, since it will be enforced on every server start.
+	AlwaysReconcileClusterRoles []string
+
+	// AdditionalControllerRoleBindings lets operators who deploy extra infra controllers grant them cluster
+	// role bindings at bootstrap time, alongside the built-in bootstrappolicy.ControllerRoleBindings() set,
+	// without forking bootstrap to add a controller. Each entry's RoleName must already be a role bootstrap
+	// reconciles - a built-in controller role, or a name added via AlwaysReconcileClusterRoles - otherwise
+	// the entry is logged and skipped. Reconciled with the same union-subjects behavior as the built-in
+	// controller role bindings, so an admin-added subject on an existing binding is preserved.
+	AdditionalControllerRoleBindings []AdditionalControllerRoleBinding
+
+	// BootstrapNamespaceLabels are applied to every namespace bootstrap creates - the infra namespace and
+	// each shared resources namespace - in addition to the fixed openshift.io/bootstrap=true marker, so
+	// cluster tooling can identify bootstrap-owned namespaces. Also patched onto a namespace that already
+	// existed when bootstrap ran. Defaults to none when unset.
+	BootstrapNamespaceLabels map[string]string
+
+	// SharedResourcesNamespaceRoleBindings, when non-empty, replaces the default project service account role
+	// bindings (see bootstrappolicy.GetBootstrapServiceAccountProjectRoleBindings) used to initialize service
+	// account role bindings in OpenShiftSharedResourcesNamespace, since a shared resources namespace often
+	// needs different access than an ordinary project - for example broader read access to shared templates.
+	// Left empty, the shared resources namespace is initialized with the same default project bindings as any
+	// other namespace.
+	SharedResourcesNamespaceRoleBindings []SharedResourcesNamespaceRoleBinding
+
+	// InfraNamespaceLabels are applied to the OpenShift infra namespace specifically, on top of
+	// BootstrapNamespaceLabels, so monitoring auto-discovery can find it. Defaults to
+	// {"openshift.io/cluster-monitoring": "true"} when unset.
+	InfraNamespaceLabels map[string]string
+
+	// InfraNamespaceQuota, when set, is created as a ResourceQuota named "infra" in the OpenShift infra
+	// namespace during bootstrap, giving infra workloads a baseline resource ceiling. Left unset (the zero
+	// value has no fields set), no ResourceQuota is created.
+	InfraNamespaceQuota *kapi.ResourceQuotaSpec
+
+	// InfraNamespaceLimitRange, when set, is created as a LimitRange named "infra" in the OpenShift infra
+	// namespace during bootstrap, giving infra workloads baseline per-container/pod resource defaults and
+	// bounds. Left unset, no LimitRange is created.
+	InfraNamespaceLimitRange *kapi.LimitRangeSpec
+
+	// InfraSCCServiceAccountLabelSelector, when non-empty, causes ensureDefaultSecurityContextConstraints to
+	// list service accounts in the infra namespace matching this label selector and union their usernames
+	// into InfraSCCServiceAccountLabelSCCName's bootstrap SCC user list, in addition to the static bootstrap
+	// set from GetBoostrapSCCAccess. This lets a dynamically added infra service account pick up its intended
+	// SCC access automatically on the next reconcile, without a code change to the static bootstrap mapping.
+	// The union is applied on top of the existing users; it never removes an already-granted user. Defaults
+	// to empty (disabled).
+	InfraSCCServiceAccountLabelSelector string
+
+	// InfraSCCServiceAccountLabelSCCName names the bootstrap SCC that InfraSCCServiceAccountLabelSelector's
+	// discovered service accounts are unioned into. Defaults to "privileged" when
+	// InfraSCCServiceAccountLabelSelector is set but this is left empty.
+	InfraSCCServiceAccountLabelSCCName string
+
+	// SCCGroupLabelSelector, when non-empty, causes ensureDefaultSecurityContextConstraints to list
+	// user.openshift.io Groups matching this label selector and union their names into
+	// SCCGroupLabelSCCName's bootstrap SCC group list, in addition to the static bootstrap set from
+	// GetBoostrapSCCAccess, on every reconcile. This lets SCC access granted via dynamic group membership -
+	// rather than a static user list - stay in sync with a group management system without a code change to
+	// the static bootstrap mapping. The union is applied on top of the existing groups; it never removes an
+	// already-granted group. Defaults to empty (disabled).
+	SCCGroupLabelSelector string
+
+	// SCCGroupLabelSCCName names the bootstrap SCC that SCCGroupLabelSelector's discovered groups are unioned
+	// into. Defaults to "privileged" when SCCGroupLabelSelector is set but this is left empty.
+	SCCGroupLabelSCCName string
+
+	// SecurityContextConstraintPriorityOverrides overrides the Priority field of named bootstrap security
+	// context constraints (for example, raising a custom SCC's priority above "anyuid") without editing the
+	// embedded bootstrap definitions. Applied before the bootstrap SCCs are compared against cluster state,
+	// so it affects creation, reconcile, and PlanSecurityContextConstraintReconcile alike. Each key must name
+	// an SCC in the bootstrap set; an unrecognized name is an error.
+	SecurityContextConstraintPriorityOverrides map[string]*int32
+
+	// Bootstrap groups the tuning knobs (retry backoffs, reconcile concurrency, dry-run/overwrite/verify
+	// decisions, and step timeouts) that govern how EnsureBootstrapPolicy's steps behave, as distinct from
+	// the identity/label/quota fields above that describe *what* bootstrap creates. See BootstrapOptions.
+	Bootstrap BootstrapOptions
+
 	// UserAgentMatchingConfig controls how API calls from *voluntarily* identifying clients will be handled.  THIS DOES NOT DEFEND AGAINST MALICIOUS CLIENTS!
 	UserAgentMatchingConfig UserAgentMatchingConfig
 }
 
+// AdditionalControllerRoleBinding names a cluster role and the subjects that should be bound to it, for an
+// infra controller deployed outside the built-in bootstrappolicy.ControllerRoleBindings set.
+type AdditionalControllerRoleBinding struct {
+	// Name is the name given to the created ClusterRoleBinding.
+	Name string
+	// RoleName is the cluster role being bound.
+	RoleName string
+	// Subjects are the service accounts, users, or groups granted RoleName.
+	Subjects []kapi.ObjectReference
+}
+
+// SharedResourcesNamespaceRoleBinding names a role and the subjects that should be bound to it in
+// PolicyConfig.OpenShiftSharedResourcesNamespace, in place of that namespace's default project service
+// account role bindings.
+type SharedResourcesNamespaceRoleBinding struct {
+	// Name is the name given to the created RoleBinding.
+	Name string
+	// RoleName is the role being bound.
+	RoleName string
+	// Subjects are the service accounts, users, or groups granted RoleName.
+	Subjects []kapi.ObjectReference
+}
+
+// BootstrapRetryBackoff describes an exponential backoff used when retrying transient bootstrap failures
+type BootstrapRetryBackoff struct {
+	// InitialIntervalSeconds is the duration, in seconds, to wait before the first retry
+	InitialIntervalSeconds int
+	// Factor is the multiplier applied to the interval after each retry
+	Factor float64
+	// Steps is the maximum number of retries attempted before giving up
+	Steps int
+	// Jitter, if greater than zero, adds random variance of up to Jitter*Duration to each computed interval, so
+	// concurrent retries don't stay synchronized against the apiserver. Ignored where zero.
+	Jitter float64
+}
+
+// BootstrapOptions groups the tuning knobs that govern how EnsureBootstrapPolicy's steps behave - retry
+// backoffs, reconcile concurrency, and the dry-run/overwrite/verify decisions each step makes - as opposed to
+// the identity/label/quota fields on PolicyConfig that describe what bootstrap creates. Keeping these together
+// gives SetDefaults, DeepCopy, and validation a single, dedicated place to live rather than being spread across
+// PolicyConfig's other fields.
+type BootstrapOptions struct {
+	// RetryBackoff controls the exponential backoff used when retrying transient failures while creating the
+	// infra and shared resources namespaces during bootstrap. A zero value uses the built-in default of a
+	// handful of quick retries.
+	RetryBackoff BootstrapRetryBackoff
+
+	// ServiceAccountRoleRetryBackoff controls the backoff used when retrying conflicts while adding service
+	// accounts to project roles in ensureNamespaceServiceAccountRoleBindings. A zero value uses the built-in
+	// default. Unlike RetryBackoff, this backoff always applies a jitter factor, so many namespaces
+	// initializing at once after a mass project creation or upgrade don't retry in lockstep against the
+	// apiserver.
+	ServiceAccountRoleRetryBackoff BootstrapRetryBackoff
+
+	// ReconcileSecurityContextConstraints, when true, causes ensureDefaultSecurityContextConstraints to update
+	// bootstrap SCCs that already exist but have drifted from their bootstrap definition, unioning in any
+	// admin-added users/groups rather than overwriting them. Defaults to false so existing clusters aren't
+	// surprised by SCC changes on upgrade.
+	ReconcileSecurityContextConstraints bool
+
+	// PruneStaleSecurityContextConstraints, when true, causes ensureDefaultSecurityContextConstraints to
+	// delete previously bootstrapped SCCs that are no longer part of the current bootstrap set. Only SCCs
+	// bootstrap itself created are ever considered for deletion; user-created SCCs are never touched.
+	// Defaults to false so upgrades never remove an SCC an admin might still be relying on.
+	PruneStaleSecurityContextConstraints bool
+
+	// StrictSCCOwnership, when true, causes ensureDefaultSecurityContextConstraints to check that every
+	// bootstrap-named SCC that already exists carries the bootstrap-owned label, and to record an error
+	// instead of silently reconciling or skipping it when the label is missing - a bootstrap-named SCC
+	// created or replaced by some other actor is a security-relevant condition worth flagging on a strict
+	// cluster. Defaults to false so ordinary clusters aren't broken by an SCC that predates this label.
+	StrictSCCOwnership bool
+
+	// SCCAPIGroupOverride forces sccClient to target a specific SCC API group instead of relying on
+	// discovery to pick between the legacy (unprefixed) endpoint and the newer security.openshift.io
+	// group. Recognized values are "legacy" and "security.openshift.io"; leaving this empty auto-detects
+	// via discovery, preferring security.openshift.io when the apiserver advertises it. Set this to pin
+	// bootstrap to one endpoint during a cluster's SCC API migration rather than following discovery.
+	SCCAPIGroupOverride string
+
+	// VerifyServiceAccountRoleBindings, when true, causes ensureNamespaceServiceAccountRoleBindings to check
+	// the actual role bindings in a namespace against the bootstrap set even when the namespace is already
+	// marked initialized, re-adding any that are missing (for example because they were manually deleted).
+	// Defaults to false so the common case stays a single fast annotation check.
+	VerifyServiceAccountRoleBindings bool
+
+	// OverwriteBootstrapPolicyIfMissing determines whether ensureComponentAuthorizationRules seeds cluster
+	// policy from BootstrapPolicyFile when no cluster policy exists yet. When not specified this option
+	// defaults to true. Disaster-recovery restores that want to reconcile namespaced roles (see
+	// ReconcileNamespacedBootstrapRoles) without risking an overwrite of restored policy can set this to false.
+	OverwriteBootstrapPolicyIfMissing *bool
+
+	// ReconcileNamespacedBootstrapRoles determines whether ensureComponentAuthorizationRules reconciles
+	// namespaced bootstrap roles and role bindings. When not specified, namespaced roles are only reconciled
+	// alongside a missing-cluster-policy bootstrap, matching the historical combined behavior. Set this to
+	// true to always reconcile namespaced roles regardless of whether cluster policy already exists, or to
+	// false to never reconcile them.
+	ReconcileNamespacedBootstrapRoles *bool
+
+	// ClusterPolicyMissingRecheckDelaySeconds bounds how long ensureComponentAuthorizationRules waits before
+	// re-checking a NotFound GetClusterPolicy result before treating cluster policy as genuinely missing and
+	// triggering the (potentially destructive) OverwriteBootstrapPolicyIfMissing seed. This guards against a
+	// transient error being misreported as NotFound: if the recheck finds cluster policy present after all,
+	// the overwrite is skipped and a warning is logged instead. Defaults to 2 seconds when zero.
+	ClusterPolicyMissingRecheckDelaySeconds int
+
+	// StepTimeoutSeconds bounds how long any single EnsureBootstrapPolicy step (component authorization
+	// rules, the infra/shared-resources namespaces, default service account roles, SCCs) may run before it's
+	// abandoned and a deadline-exceeded error is recorded against that step, so a slow or wedged
+	// apiserver/etcd can't hang master startup indefinitely. Defaults to 120 seconds when zero.
+	StepTimeoutSeconds int
+
+	// NamespaceInitConcurrency is how many namespaces have their service account role bindings initialized
+	// at once during a mass initialization sweep (for example, over every project during an upgrade).
+	// Defaults to 10 when unset. Each concurrent initialization issues its own apiserver requests, so raising
+	// this competes with other in-process controllers sharing the loopback client for QPS/burst budget.
+	NamespaceInitConcurrency int
+
+	// StepFailurePolicies overrides whether a named EnsureBootstrapPolicy step (see the BootstrapStep* name
+	// constants) is Required or Optional on failure. Steps not listed here fall back to their built-in default
+	// classification: shared_resources_namespace and scc default to Required, matching their historical
+	// fail-fast behavior, while the remaining steps default to Optional, matching their historical
+	// best-effort-with-logging behavior. Use this to make a Required step Optional so its failures no longer
+	// halt startup, or an Optional step Required so a failure that used to only be logged now aborts bootstrap.
+	StepFailurePolicies map[string]BootstrapStepFailurePolicy
+
+	// SkipDefaultNamespaceInitialization, when true, causes ensureDefaultNamespaceServiceAccountRoles to skip
+	// waiting for and initializing the default namespace's service account role bindings, logging at info
+	// level instead of treating a never-appearing default namespace as an error. Set this on minimal or
+	// edge deployments that intentionally omit the default namespace. Defaults to false, preserving the
+	// historical behavior of waiting for and erroring on a missing default namespace.
+	SkipDefaultNamespaceInitialization bool
+
+	// VerifyBootstrapRoleBindingIntegrity, when true, causes EnsureBootstrapPolicy to run
+	// MasterConfig.DetectOrphanedBootstrapRoleBindings and log any bootstrap-owned cluster role binding whose
+	// RoleRef no longer resolves to an existing cluster role - typically left behind when a role is renamed
+	// or removed across an upgrade. Detection itself is always available via the method regardless of this
+	// setting; this only controls whether bootstrap runs it automatically. Defaults to false.
+	VerifyBootstrapRoleBindingIntegrity bool
+
+	// PruneOrphanedBootstrapRoleBindings, when true, causes an orphaned bootstrap role binding found by
+	// VerifyBootstrapRoleBindingIntegrity to be deleted rather than only logged. Has no effect unless
+	// VerifyBootstrapRoleBindingIntegrity is also true. Defaults to false, since deleting a role binding an
+	// admin may still be relying on is destructive; prefer investigating the logged orphans first.
+	PruneOrphanedBootstrapRoleBindings bool
+
+	// DisabledSteps lists the EnsureBootstrapPolicy steps (see the BootstrapStep* name constants) that should
+	// be skipped entirely rather than run. A disabled step's BootstrapStepResult records "skipped" instead of
+	// whatever counts it would otherwise have produced, and its StepFailurePolicy is never consulted since it
+	// never runs and so can never fail. Use this to leave a step's resources exactly as they are - for example
+	// on a deployment that manages SCCs itself and never wants EnsureBootstrapPolicy to touch them. Defaults to
+	// nil, meaning every step runs.
+	DisabledSteps map[string]bool
+
+	// InfraNamespaceTerminatingWaitSeconds bounds how long the infra_namespace step waits for the infra
+	// namespace to leave the Terminating phase - left behind by a very recent `oc delete namespace` racing
+	// with this bootstrap run's own re-creation - before giving up with a descriptive error. Defaults to 30
+	// seconds when zero.
+	InfraNamespaceTerminatingWaitSeconds int
+
+	// ReconcileLoopEnabled, when true, causes MasterConfig.StartBootstrapReconcileLoop to actually start its
+	// background reconcile loop instead of being a no-op. The loop periodically re-corrects drift in cluster
+	// roles and security context constraints between master restarts. Defaults to false: continuously
+	// re-checking cluster-scoped policy has a resource cost most deployments don't need on top of the
+	// reconciliation EnsureBootstrapPolicy already does at startup.
+	ReconcileLoopEnabled bool
+
+	// VerifySCCServiceAccountReferences, when true, causes ensureDefaultSecurityContextConstraints to check
+	// that every service account referenced by a bootstrap SCC's Users list (see
+	// bootstrappolicy.GetBoostrapSCCAccess) actually exists, logging a warning for any that's missing so an
+	// SCC never silently grants access to a principal bootstrap forgot to create. Defaults to false.
+	VerifySCCServiceAccountReferences bool
+
+	// CreateMissingSCCServiceAccounts, when true, causes a missing service account found by
+	// VerifySCCServiceAccountReferences to be created instead of only logged. Has no effect unless
+	// VerifySCCServiceAccountReferences is also true. Defaults to false, since creating a service account an
+	// admin didn't ask for is a more surprising action than just warning about the gap.
+	CreateMissingSCCServiceAccounts bool
+
+	// ReadinessTimeoutSeconds bounds how long EnsureBootstrapPolicy waits, before running any step, for the
+	// apiserver to answer a cheap readiness probe (listing namespaces with a limit of 1). During combined
+	// startup the loopback client may reach bootstrap before the apiserver is done initializing, and probing
+	// first avoids every early step failing with a spurious connection error. Defaults to 30 seconds when
+	// zero.
+	ReadinessTimeoutSeconds int
+
+	// ClientConnectionOverrides, when set, causes bootstrap to build its own apiserver client scoped to
+	// these QPS/burst settings instead of reusing the shared loopback client. A fresh cluster's initial
+	// reconcile storm - creating every bootstrap role, binding, and SCC in one pass - can otherwise consume
+	// enough of the loopback client's shared QPS/burst budget to starve other in-process controllers.
+	// Defaults to nil, meaning bootstrap reuses the existing loopback client unscoped.
+	ClientConnectionOverrides *ClientConnectionOverrides
+
+	// TolerateNamespaceCreationForbidden, when true, causes ensureNamespaceExists to treat a Forbidden error
+	// creating a missing shared resources namespace as informational rather than an error, on the assumption
+	// that some external process is responsible for creating it. Supports split-responsibility deployments
+	// where the loopback identity is intentionally not granted namespace creation. Has no effect once the
+	// namespace exists, and does not apply to the infra namespace, which bootstrap always needs to be able to
+	// create. Defaults to false, preserving the historical behavior of treating Forbidden as an error.
+	TolerateNamespaceCreationForbidden bool
+}
+
+// IsStepDisabled reports whether step is listed in o.DisabledSteps.
+func (o BootstrapOptions) IsStepDisabled(step string) bool {
+	return o.DisabledSteps[step]
+}
+
+// BootstrapStepFailurePolicy classifies whether a failed EnsureBootstrapPolicy step should abort bootstrap or
+// merely be recorded on its BootstrapStepResult and left for the step to retry on the next master restart.
+type BootstrapStepFailurePolicy string
+
+const (
+	// BootstrapStepRequired aborts EnsureBootstrapPolicy as soon as the step fails, returning its error and
+	// leaving any later steps unattempted.
+	BootstrapStepRequired BootstrapStepFailurePolicy = "Required"
+	// BootstrapStepOptional records the step's failure on its BootstrapStepResult and lets EnsureBootstrapPolicy
+	// continue on to the remaining steps.
+	BootstrapStepOptional BootstrapStepFailurePolicy = "Optional"
+)
+
+// Bootstrap step names accepted as keys in BootstrapOptions.StepFailurePolicies. These match the step names
+// EnsureBootstrapPolicy passes to observeBootstrapStep and BootstrapResult.step.
+const (
+	BootstrapStepComponentAuthz           = "component_authz"
+	BootstrapStepInfraNamespace           = "infra_namespace"
+	BootstrapStepSharedResourcesNamespace = "shared_resources_namespace"
+	BootstrapStepDefaultNamespaceSARoles  = "default_namespace_sa_roles"
+	BootstrapStepSCC                      = "scc"
+)
+
+// defaultBootstrapStepFailurePolicies is the built-in Required/Optional classification applied to a step
+// whenever StepFailurePolicies doesn't override it. It matches EnsureBootstrapPolicy's historical behavior:
+// shared_resources_namespace and scc already aborted bootstrap on failure, while the rest were already
+// best-effort.
+var defaultBootstrapStepFailurePolicies = map[string]BootstrapStepFailurePolicy{
+	BootstrapStepComponentAuthz:           BootstrapStepOptional,
+	BootstrapStepInfraNamespace:           BootstrapStepOptional,
+	BootstrapStepSharedResourcesNamespace: BootstrapStepRequired,
+	BootstrapStepDefaultNamespaceSARoles:  BootstrapStepOptional,
+	BootstrapStepSCC:                      BootstrapStepRequired,
+}
+
+// StepFailurePolicy returns the effective Required/Optional classification for step, applying any override in
+// o.StepFailurePolicies over the built-in default.
+func (o BootstrapOptions) StepFailurePolicy(step string) BootstrapStepFailurePolicy {
+	if policy, ok := o.StepFailurePolicies[step]; ok {
+		return policy
+	}
+	return defaultBootstrapStepFailurePolicies[step]
+}
+
+// defaultBootstrapOptionsClusterPolicyMissingRecheckDelaySeconds, defaultBootstrapOptionsStepTimeoutSeconds,
+// and defaultBootstrapOptionsNamespaceInitConcurrency are the values SetDefaults applies to a zero-valued
+// BootstrapOptions.
+const (
+	defaultBootstrapOptionsClusterPolicyMissingRecheckDelaySeconds = 2
+	defaultBootstrapOptionsStepTimeoutSeconds                      = 120
+	defaultBootstrapOptionsNamespaceInitConcurrency                = 10
+	defaultBootstrapOptionsInfraNamespaceTerminatingWaitSeconds    = 30
+	defaultBootstrapOptionsReadinessTimeoutSeconds                 = 30
+)
+
+// SetDefaults fills in any of o's fields that are left at their zero value with their documented default,
+// including defaulting OverwriteBootstrapPolicyIfMissing to true. It's safe to call more than once: fields
+// that already hold a non-zero value are left untouched.
+func (o *BootstrapOptions) SetDefaults() {
+	if o.ClusterPolicyMissingRecheckDelaySeconds == 0 {
+		o.ClusterPolicyMissingRecheckDelaySeconds = defaultBootstrapOptionsClusterPolicyMissingRecheckDelaySeconds
+	}
+	if o.StepTimeoutSeconds == 0 {
+		o.StepTimeoutSeconds = defaultBootstrapOptionsStepTimeoutSeconds
+	}
+	if o.NamespaceInitConcurrency == 0 {
+		o.NamespaceInitConcurrency = defaultBootstrapOptionsNamespaceInitConcurrency
+	}
+	if o.InfraNamespaceTerminatingWaitSeconds == 0 {
+		o.InfraNamespaceTerminatingWaitSeconds = defaultBootstrapOptionsInfraNamespaceTerminatingWaitSeconds
+	}
+	if o.ReadinessTimeoutSeconds == 0 {
+		o.ReadinessTimeoutSeconds = defaultBootstrapOptionsReadinessTimeoutSeconds
+	}
+	if o.OverwriteBootstrapPolicyIfMissing == nil {
+		overwrite := true
+		o.OverwriteBootstrapPolicyIfMissing = &overwrite
+	}
+}
+
+// DeepCopy returns a copy of o that shares no mutable state with it, so the two can be defaulted or mutated
+// independently.
+func (o BootstrapOptions) DeepCopy() BootstrapOptions {
+	out := o
+	if o.OverwriteBootstrapPolicyIfMissing != nil {
+		overwrite := *o.OverwriteBootstrapPolicyIfMissing
+		out.OverwriteBootstrapPolicyIfMissing = &overwrite
+	}
+	if o.ReconcileNamespacedBootstrapRoles != nil {
+		reconcile := *o.ReconcileNamespacedBootstrapRoles
+		out.ReconcileNamespacedBootstrapRoles = &reconcile
+	}
+	if o.StepFailurePolicies != nil {
+		out.StepFailurePolicies = make(map[string]BootstrapStepFailurePolicy, len(o.StepFailurePolicies))
+		for step, policy := range o.StepFailurePolicies {
+			out.StepFailurePolicies[step] = policy
+		}
+	}
+	if o.DisabledSteps != nil {
+		out.DisabledSteps = make(map[string]bool, len(o.DisabledSteps))
+		for step, disabled := range o.DisabledSteps {
+			out.DisabledSteps[step] = disabled
+		}
+	}
+	return out
+}
+
 // UserAgentMatchingConfig controls how API calls from *voluntarily* identifying clients will be handled.  THIS DOES NOT DEFEND AGAINST MALICIOUS CLIENTS!
 type UserAgentMatchingConfig struct {
 	// If this list is non-empty, then a User-Agent must match one of the UserAgentRegexes to be allowed
@@ -213,3 +213,110 @@ func TestFeatureListHasWithUnknownValue(t *testing.T) {
 	testFeatureListCases(t, fl, goodCases, true)
 	testFeatureListCases(t, fl, badCases, false)
 }
+
+func TestBootstrapOptionsSetDefaultsAppliesDefaultsToZeroValue(t *testing.T) {
+	o := BootstrapOptions{}
+	o.SetDefaults()
+
+	if o.ClusterPolicyMissingRecheckDelaySeconds != defaultBootstrapOptionsClusterPolicyMissingRecheckDelaySeconds {
+		t.Errorf("expected ClusterPolicyMissingRecheckDelaySeconds to default to %d, got %d", defaultBootstrapOptionsClusterPolicyMissingRecheckDelaySeconds, o.ClusterPolicyMissingRecheckDelaySeconds)
+	}
+	if o.StepTimeoutSeconds != defaultBootstrapOptionsStepTimeoutSeconds {
+		t.Errorf("expected StepTimeoutSeconds to default to %d, got %d", defaultBootstrapOptionsStepTimeoutSeconds, o.StepTimeoutSeconds)
+	}
+	if o.NamespaceInitConcurrency != defaultBootstrapOptionsNamespaceInitConcurrency {
+		t.Errorf("expected NamespaceInitConcurrency to default to %d, got %d", defaultBootstrapOptionsNamespaceInitConcurrency, o.NamespaceInitConcurrency)
+	}
+	if o.OverwriteBootstrapPolicyIfMissing == nil || !*o.OverwriteBootstrapPolicyIfMissing {
+		t.Errorf("expected OverwriteBootstrapPolicyIfMissing to default to true, got %v", o.OverwriteBootstrapPolicyIfMissing)
+	}
+	if o.ReconcileNamespacedBootstrapRoles != nil {
+		t.Errorf("expected ReconcileNamespacedBootstrapRoles to stay unset, got %v", *o.ReconcileNamespacedBootstrapRoles)
+	}
+}
+
+func TestBootstrapOptionsSetDefaultsPreservesConfiguredValues(t *testing.T) {
+	overwrite := false
+	o := BootstrapOptions{
+		NamespaceInitConcurrency:          4,
+		OverwriteBootstrapPolicyIfMissing: &overwrite,
+	}
+	o.SetDefaults()
+
+	if o.NamespaceInitConcurrency != 4 {
+		t.Errorf("expected the configured NamespaceInitConcurrency to be preserved, got %d", o.NamespaceInitConcurrency)
+	}
+	if o.OverwriteBootstrapPolicyIfMissing == nil || *o.OverwriteBootstrapPolicyIfMissing {
+		t.Errorf("expected the configured OverwriteBootstrapPolicyIfMissing to be preserved, got %v", o.OverwriteBootstrapPolicyIfMissing)
+	}
+}
+
+func TestBootstrapOptionsDeepCopyIsIndependent(t *testing.T) {
+	overwrite := true
+	o := BootstrapOptions{
+		OverwriteBootstrapPolicyIfMissing: &overwrite,
+		StepFailurePolicies:               map[string]BootstrapStepFailurePolicy{BootstrapStepSCC: BootstrapStepOptional},
+		DisabledSteps:                     map[string]bool{BootstrapStepSCC: true},
+	}
+
+	out := o.DeepCopy()
+	*out.OverwriteBootstrapPolicyIfMissing = false
+	out.StepFailurePolicies[BootstrapStepSCC] = BootstrapStepRequired
+	out.DisabledSteps[BootstrapStepSCC] = false
+
+	if !*o.OverwriteBootstrapPolicyIfMissing {
+		t.Errorf("expected mutating the copy's pointer field not to affect the original")
+	}
+	if o.StepFailurePolicies[BootstrapStepSCC] != BootstrapStepOptional {
+		t.Errorf("expected mutating the copy's map not to affect the original")
+	}
+	if !o.DisabledSteps[BootstrapStepSCC] {
+		t.Errorf("expected mutating the copy's DisabledSteps not to affect the original")
+	}
+}
+
+func TestBootstrapOptionsStepFailurePolicyUsesBuiltInDefaults(t *testing.T) {
+	o := BootstrapOptions{}
+
+	requiredSteps := []string{BootstrapStepSharedResourcesNamespace, BootstrapStepSCC}
+	for _, step := range requiredSteps {
+		if policy := o.StepFailurePolicy(step); policy != BootstrapStepRequired {
+			t.Errorf("expected %s to default to Required, got %s", step, policy)
+		}
+	}
+
+	optionalSteps := []string{BootstrapStepComponentAuthz, BootstrapStepInfraNamespace, BootstrapStepDefaultNamespaceSARoles}
+	for _, step := range optionalSteps {
+		if policy := o.StepFailurePolicy(step); policy != BootstrapStepOptional {
+			t.Errorf("expected %s to default to Optional, got %s", step, policy)
+		}
+	}
+}
+
+func TestBootstrapOptionsStepFailurePolicyHonorsOverride(t *testing.T) {
+	o := BootstrapOptions{
+		StepFailurePolicies: map[string]BootstrapStepFailurePolicy{BootstrapStepSCC: BootstrapStepOptional},
+	}
+
+	if policy := o.StepFailurePolicy(BootstrapStepSCC); policy != BootstrapStepOptional {
+		t.Errorf("expected the override to take effect over the built-in Required default, got %s", policy)
+	}
+}
+
+func TestBootstrapOptionsIsStepDisabled(t *testing.T) {
+	o := BootstrapOptions{
+		DisabledSteps: map[string]bool{BootstrapStepSCC: true},
+	}
+
+	if !o.IsStepDisabled(BootstrapStepSCC) {
+		t.Errorf("expected %s to be reported disabled", BootstrapStepSCC)
+	}
+	if o.IsStepDisabled(BootstrapStepComponentAuthz) {
+		t.Errorf("expected %s to be reported enabled by default", BootstrapStepComponentAuthz)
+	}
+
+	empty := BootstrapOptions{}
+	if empty.IsStepDisabled(BootstrapStepSCC) {
+		t.Errorf("expected a nil DisabledSteps map to disable nothing")
+	}
+}
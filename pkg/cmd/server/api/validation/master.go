@@ -10,12 +10,14 @@ import (
 	"strings"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	knet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
 	kuval "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	apiserveroptions "k8s.io/kubernetes/cmd/kube-apiserver/app/options"
 	kcmoptions "k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
+	kapi "k8s.io/kubernetes/pkg/api"
 	kvalidation "k8s.io/kubernetes/pkg/api/validation"
 	"k8s.io/kubernetes/pkg/serviceaccount"
 
@@ -640,6 +642,58 @@ func ValidatePolicyConfig(config api.PolicyConfig, fldPath *field.Path) field.Er
 		}
 	}
 
+	allErrs = append(allErrs, ValidateBootstrapOptions(config.Bootstrap, fldPath.Child("bootstrap"))...)
+
+	if config.InfraNamespaceQuota != nil {
+		allErrs = append(allErrs, kvalidation.ValidateResourceQuotaSpec(config.InfraNamespaceQuota, fldPath.Child("infraNamespaceQuota"))...)
+	}
+
+	if config.InfraNamespaceLimitRange != nil {
+		limitRange := &kapi.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "infra", Namespace: "openshift-infra"},
+			Spec:       *config.InfraNamespaceLimitRange,
+		}
+		for _, err := range kvalidation.ValidateLimitRange(limitRange) {
+			if strings.HasPrefix(err.Field, "spec") {
+				err.Field = fldPath.Child("infraNamespaceLimitRange").String() + strings.TrimPrefix(err.Field, "spec")
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// maxBootstrapConcurrency caps BootstrapOptions.NamespaceInitConcurrency, so a mistyped config value can't
+// fork off an unbounded number of goroutines or apiserver requests during bootstrap.
+const maxBootstrapConcurrency = 64
+
+// ValidateBootstrapOptions validates the tuning knobs that govern EnsureBootstrapPolicy's bootstrap steps.
+func ValidateBootstrapOptions(config api.BootstrapOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config.ClusterPolicyMissingRecheckDelaySeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("clusterPolicyMissingRecheckDelaySeconds"), config.ClusterPolicyMissingRecheckDelaySeconds, "must not be negative"))
+	}
+
+	if config.StepTimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("stepTimeoutSeconds"), config.StepTimeoutSeconds, "must not be negative"))
+	}
+
+	if config.NamespaceInitConcurrency < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("namespaceInitConcurrency"), config.NamespaceInitConcurrency, "must not be negative"))
+	} else if config.NamespaceInitConcurrency > maxBootstrapConcurrency {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("namespaceInitConcurrency"), config.NamespaceInitConcurrency, fmt.Sprintf("must not be greater than %d", maxBootstrapConcurrency)))
+	}
+
+	supportedStepFailurePolicies := sets.NewString(string(api.BootstrapStepRequired), string(api.BootstrapStepOptional))
+	stepFailurePoliciesPath := fldPath.Child("stepFailurePolicies")
+	for step, policy := range config.StepFailurePolicies {
+		if !supportedStepFailurePolicies.Has(string(policy)) {
+			allErrs = append(allErrs, field.NotSupported(stepFailurePoliciesPath.Key(step), policy, supportedStepFailurePolicies.List()))
+		}
+	}
+
 	return allErrs
 }
 
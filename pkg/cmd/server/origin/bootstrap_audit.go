@@ -0,0 +1,55 @@
+package origin
+
+import "time"
+
+// bootstrapAuditActor identifies the privileged loopback identity that performs bootstrap mutations in
+// every BootstrapAuditRecord, since those mutations are made by the master process itself rather than by
+// any request-scoped user and are otherwise invisible to normal apiserver audit logging.
+const bootstrapAuditActor = "bootstrap"
+
+// BootstrapAuditRecord describes a single privileged mutation an ensure* bootstrap method made, for
+// deployments that need an auditable trail of what bootstrap changed independent of the apiserver's request
+// audit log.
+type BootstrapAuditRecord struct {
+	// Actor is always bootstrapAuditActor; included on the record itself so a BootstrapAuditor can log or
+	// forward records without reaching back into the MasterConfig that produced them.
+	Actor string
+	// Action names the kind of mutation, for example "namespace-create" or "scc-reconcile".
+	Action string
+	// Object names the specific resource the mutation applied to, for example a namespace or SCC name.
+	Object string
+	// Timestamp is when the mutation was performed.
+	Timestamp time.Time
+}
+
+// BootstrapAuditor receives a BootstrapAuditRecord for every privileged mutation ensure* bootstrap methods
+// make. It's optional: MasterConfig.BootstrapAuditor may be left nil, in which case bootstrapAuditor()
+// returns a no-op implementation and audited deployments pay nothing.
+type BootstrapAuditor interface {
+	Record(record BootstrapAuditRecord)
+}
+
+// noopBootstrapAuditor is the default BootstrapAuditor, discarding every record.
+type noopBootstrapAuditor struct{}
+
+func (noopBootstrapAuditor) Record(record BootstrapAuditRecord) {}
+
+// bootstrapAuditor returns the configured BootstrapAuditor, falling back to a no-op implementation so
+// callers can always record a mutation unconditionally.
+func (c *MasterConfig) bootstrapAuditor() BootstrapAuditor {
+	if c.BootstrapAuditor != nil {
+		return c.BootstrapAuditor
+	}
+	return noopBootstrapAuditor{}
+}
+
+// auditBootstrapMutation records that a privileged bootstrap mutation was made, for auditing loopback-client
+// changes that would otherwise be invisible to the apiserver's request audit log.
+func (c *MasterConfig) auditBootstrapMutation(action, object string) {
+	c.bootstrapAuditor().Record(BootstrapAuditRecord{
+		Actor:     bootstrapAuditActor,
+		Action:    action,
+		Object:    object,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,44 @@
+package origin
+
+import (
+	"context"
+	"fmt"
+)
+
+// postBootstrapHook is a named function registered via RegisterPostBootstrapHook.
+type postBootstrapHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// RegisterPostBootstrapHook registers fn to run once EnsureBootstrapPolicy's built-in steps have completed
+// successfully, in registration order. This is the supported extension point for one-time initialization that
+// depends on bootstrap having finished - installing default templates, seeding image streams - instead of
+// patching ensure.go directly. Hooks do not run if a required bootstrap step failed; a hook's own error is
+// recorded on the "post_bootstrap_hooks" BootstrapStepResult rather than preventing later hooks from running.
+func (c *MasterConfig) RegisterPostBootstrapHook(name string, fn func(ctx context.Context) error) {
+	c.postBootstrapHooksMu.Lock()
+	defer c.postBootstrapHooksMu.Unlock()
+	c.postBootstrapHooks = append(c.postBootstrapHooks, postBootstrapHook{name: name, fn: fn})
+}
+
+// runPostBootstrapHooks runs every hook registered via RegisterPostBootstrapHook, in registration order,
+// recording each hook's outcome on result's "post_bootstrap_hooks" step. It's only called once the required
+// bootstrap steps have already succeeded, so a hook can assume the standard bootstrap objects are in place.
+func (c *MasterConfig) runPostBootstrapHooks(ctx context.Context, result *BootstrapResult) {
+	c.postBootstrapHooksMu.Lock()
+	hooks := make([]postBootstrapHook, len(c.postBootstrapHooks))
+	copy(hooks, c.postBootstrapHooks)
+	c.postBootstrapHooksMu.Unlock()
+
+	step := result.step("post_bootstrap_hooks")
+	for _, hook := range hooks {
+		if err := hook.fn(ctx); err != nil {
+			err = fmt.Errorf("post-bootstrap hook %q failed: %v", hook.name, err)
+			c.bootstrapLog().Error(err, "Post-bootstrap hook failed", "hook", hook.name)
+			step.addError(err)
+			continue
+		}
+		step.inc("succeeded")
+	}
+}
@@ -0,0 +1,51 @@
+package origin
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/glog"
+)
+
+// BootstrapLogger is the structured logging interface used by the ensure* bootstrap methods. Unlike
+// glog.Infof/Errorf's format strings, kv pairs (namespace=, role=, step=, ...) stay queryable by a
+// centralized logging pipeline instead of being buried inside a message. kv must be an even-length list of
+// alternating keys and values, following the same convention as klog's structured logging and controller-runtime's logr.
+type BootstrapLogger interface {
+	// Info logs a informational message with structured fields.
+	Info(msg string, kv ...interface{})
+	// Error logs err alongside msg and structured fields.
+	Error(err error, msg string, kv ...interface{})
+}
+
+// glogBootstrapLogger is the default BootstrapLogger, formatting fields onto glog's existing output so
+// behavior is unchanged for deployments that already scrape glog.
+type glogBootstrapLogger struct{}
+
+func (glogBootstrapLogger) Info(msg string, kv ...interface{}) {
+	glog.Infof("%s%s", msg, formatBootstrapLogFields(kv))
+}
+
+func (glogBootstrapLogger) Error(err error, msg string, kv ...interface{}) {
+	glog.Errorf("%s%s: %v", msg, formatBootstrapLogFields(kv), err)
+}
+
+// formatBootstrapLogFields renders kv as " key=value key2=value2", or the empty string when kv is empty.
+// An odd-length kv has its trailing key rendered with a "MISSING" value rather than panicking, since a
+// logging call is never worth crashing over.
+func formatBootstrapLogFields(kv []interface{}) string {
+	if len(kv) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(kv); i += 2 {
+		key := kv[i]
+		value := interface{}("MISSING")
+		if i+1 < len(kv) {
+			value = kv[i+1]
+		}
+		fmt.Fprintf(&buf, " %v=%v", key, value)
+	}
+	return buf.String()
+}
@@ -0,0 +1,58 @@
+package origin
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	bootstrapMetricsNamespace = "openshift"
+	bootstrapMetricsSubsystem = "bootstrap"
+)
+
+var (
+	bootstrapEnsureDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: bootstrapMetricsNamespace,
+			Subsystem: bootstrapMetricsSubsystem,
+			Name:      "ensure_duration_seconds",
+			Help:      "Time it took each bootstrap policy initialization step to run, labeled by step.",
+		},
+		[]string{"step"},
+	)
+	bootstrapEnsureErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: bootstrapMetricsNamespace,
+			Subsystem: bootstrapMetricsSubsystem,
+			Name:      "ensure_errors_total",
+			Help:      "Number of times a bootstrap policy initialization step returned an error, labeled by step.",
+		},
+		[]string{"step"},
+	)
+
+	registerBootstrapMetrics sync.Once
+)
+
+// RegisterBootstrapMetrics registers the bootstrap ensure_duration_seconds and ensure_errors_total metrics
+// with the default Prometheus registry. It is safe to call more than once - for example from multiple
+// MasterConfig instances in tests - since registration only happens on the first call.
+func RegisterBootstrapMetrics() {
+	registerBootstrapMetrics.Do(func() {
+		prometheus.MustRegister(bootstrapEnsureDuration)
+		prometheus.MustRegister(bootstrapEnsureErrors)
+	})
+}
+
+// observeBootstrapStep records how long fn took to run under the ensure_duration_seconds histogram, and
+// increments ensure_errors_total when fn returns a non-nil error, both labeled by step.
+func observeBootstrapStep(step string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	bootstrapEnsureDuration.WithLabelValues(step).Observe(time.Since(start).Seconds())
+	if err != nil {
+		bootstrapEnsureErrors.WithLabelValues(step).Inc()
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+package origin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/origin/pkg/oc/admin/policy"
+)
+
+// bootstrapReconcileLoopClock returns the clock StartBootstrapReconcileLoop schedules its ticks with,
+// falling back to the real wall clock. Tests substitute a clock.FakeClock (via MasterConfig's
+// BootstrapReconcileLoopClock field) to drive the loop deterministically instead of sleeping in real time.
+func (c *MasterConfig) bootstrapReconcileLoopClock() clock.Clock {
+	if c.BootstrapReconcileLoopClock != nil {
+		return c.BootstrapReconcileLoopClock
+	}
+	return &clock.RealClock{}
+}
+
+// StartBootstrapReconcileLoop launches a background goroutine that periodically re-runs
+// bootstrapReconcileOnce every interval, correcting drift in cluster roles (including the discovery role)
+// and security context constraints without requiring a master restart. It does nothing unless
+// PolicyConfig.Bootstrap.ReconcileLoopEnabled is set, since continuously re-checking cluster-scoped policy
+// has a resource cost most deployments don't need. The loop honors ctx cancellation and never overlaps runs -
+// each run completes before the timer for the next one starts - so a run that takes longer than interval
+// simply delays the following one rather than stacking up concurrent reconciles.
+func (c *MasterConfig) StartBootstrapReconcileLoop(ctx context.Context, interval time.Duration) {
+	if !c.Options.PolicyConfig.Bootstrap.ReconcileLoopEnabled {
+		return
+	}
+
+	ticker := c.bootstrapReconcileLoopClock().NewTicker(interval)
+	go c.runBootstrapReconcileLoop(ctx, ticker, c.bootstrapReconcileOnce)
+}
+
+// runBootstrapReconcileLoop drives run once per tick received from ticker until ctx is done, at which point
+// it stops ticker and returns. Split out from StartBootstrapReconcileLoop so tests can drive it directly with
+// a clock.FakeClock-backed ticker and a counting stand-in for run, without waiting on real time or standing
+// up fake API servers for a full bootstrapReconcileOnce.
+func (c *MasterConfig) runBootstrapReconcileLoop(ctx context.Context, ticker clock.Ticker, run func() error) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := run(); err != nil {
+				c.bootstrapLog().Error(err, "Bootstrap reconcile loop run failed")
+			}
+		}
+	}
+}
+
+// bootstrapReconcileOnce runs the reconcile-only subset of bootstrap policy - cluster roles (including the
+// discovery role, via clusterRolesToReconcile) and security context constraints - correcting whatever drift
+// it finds and logging each correction made. It's the unit of work StartBootstrapReconcileLoop repeats on an
+// interval; unlike EnsureBootstrapPolicy it never touches namespaces, role bindings, or namespaced roles,
+// since a background drift-correction loop has no business creating or deleting those, only keeping
+// already-established cluster-scoped policy converged.
+func (c *MasterConfig) bootstrapReconcileOnce() error {
+	errs := []error{}
+
+	if err := c.reconcileClusterRolesOnce(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.reconcileSecurityContextConstraintsOnce(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// reconcileClusterRolesOnce corrects any of clusterRolesToReconcile that have drifted from their bootstrap
+// definition, logging the names it corrects. A cluster role that already matches its bootstrap definition is
+// left untouched and produces no log line, so a healthy loop stays quiet.
+func (c *MasterConfig) reconcileClusterRolesOnce() error {
+	reconcileRoles := &policy.ReconcileClusterRolesOptions{
+		RolesToReconcile: c.clusterRolesToReconcile(),
+		Union:            true,
+		Out:              c.bootstrapOutput(),
+		RoleClient:       c.PrivilegedLoopbackOpenShiftClient.ClusterRoles(),
+	}
+
+	changed, _, err := reconcileRoles.ChangedClusterRoles()
+	if err != nil {
+		return fmt.Errorf("could not check cluster roles for drift: %v", err)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(changed))
+	for _, role := range changed {
+		names = append(names, role.Name)
+	}
+	c.bootstrapLog().Info("Bootstrap reconcile loop correcting drifted cluster roles", "roles", names)
+
+	if c.DryRunBootstrap {
+		return nil
+	}
+	if err := reconcileRoles.ReplaceChangedRoles(changed); err != nil {
+		return fmt.Errorf("could not reconcile cluster roles: %v", err)
+	}
+	return nil
+}
+
+// reconcileSecurityContextConstraintsOnce corrects any bootstrap security context constraint
+// SecurityContextConstraintsStatus finds missing or drifted, logging what it corrects. A cluster whose SCCs
+// already match their bootstrap definitions is left untouched and produces no log line.
+func (c *MasterConfig) reconcileSecurityContextConstraintsOnce() error {
+	status, err := c.SecurityContextConstraintsStatus()
+	if err != nil {
+		return fmt.Errorf("could not check security context constraint status: %v", err)
+	}
+	if status.Status == SCCHealthStatusHealthy {
+		return nil
+	}
+
+	c.bootstrapLog().Info("Bootstrap reconcile loop correcting drifted security context constraints", "missing", status.Missing, "drifted", status.Drifted)
+	if c.DryRunBootstrap {
+		return nil
+	}
+	if err := c.ReconcileSecurityContextConstraints(); err != nil {
+		return fmt.Errorf("could not reconcile security context constraints: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package origin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// TestRunBootstrapReconcileLoopRunsOncePerTick verifies that runBootstrapReconcileLoop calls run exactly
+// once for each tick a clock.FakeClock delivers, and stops calling it once the fake clock has been advanced
+// past the number of ticks under test - proving the loop is driven by the clock rather than by real time.
+func TestRunBootstrapReconcileLoopRunsOncePerTick(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	ticker := fakeClock.NewTicker(time.Second)
+
+	var runs int32
+	ran := make(chan struct{}, 8)
+	run := func() error {
+		atomic.AddInt32(&runs, 1)
+		ran <- struct{}{}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &MasterConfig{}
+	done := make(chan struct{})
+	go func() {
+		c.runBootstrapReconcileLoop(ctx, ticker, run)
+		close(done)
+	}()
+
+	const wantRuns = 3
+	for i := 0; i < wantRuns; i++ {
+		fakeClock.Step(time.Second)
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for run %d", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for runBootstrapReconcileLoop to exit after cancellation")
+	}
+
+	if got := atomic.LoadInt32(&runs); got != wantRuns {
+		t.Errorf("expected run to be called %d times, got %d", wantRuns, got)
+	}
+}
+
+// TestRunBootstrapReconcileLoopDoesNotOverlapRuns verifies that a slow run blocks the next tick's run from
+// starting until it returns - runBootstrapReconcileLoop only re-enters its select loop, and so only becomes
+// eligible to receive the next tick, once run has returned.
+func TestRunBootstrapReconcileLoopDoesNotOverlapRuns(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	ticker := fakeClock.NewTicker(time.Second)
+
+	var concurrent, maxConcurrent int32
+	release := make(chan struct{})
+	started := make(chan struct{}, 8)
+	run := func() error {
+		started <- struct{}{}
+		n := atomic.AddInt32(&concurrent, 1)
+		if n > atomic.LoadInt32(&maxConcurrent) {
+			atomic.StoreInt32(&maxConcurrent, n)
+		}
+		<-release
+		atomic.AddInt32(&concurrent, -1)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := &MasterConfig{}
+	go c.runBootstrapReconcileLoop(ctx, ticker, run)
+
+	fakeClock.Step(time.Second)
+	<-started
+
+	// A second tick fires while the first run is still blocked on release; it must not start a concurrent run.
+	fakeClock.Step(time.Second)
+	select {
+	case <-started:
+		t.Fatal("expected the second tick's run not to start until the first run finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if got := atomic.LoadInt32(&maxConcurrent); got > 1 {
+		t.Errorf("expected at most one run in flight at a time, observed %d", got)
+	}
+}
+
+// TestStartBootstrapReconcileLoopNoopWhenDisabled verifies that StartBootstrapReconcileLoop does not start a
+// background loop at all when PolicyConfig.Bootstrap.ReconcileLoopEnabled is left at its default of false.
+func TestStartBootstrapReconcileLoopNoopWhenDisabled(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	c := &MasterConfig{BootstrapReconcileLoopClock: fakeClock}
+
+	c.StartBootstrapReconcileLoop(context.Background(), time.Second)
+
+	// If a loop had started, stepping the clock enough times to fire many ticks and letting the scheduler run
+	// would panic on a nil PrivilegedLoopbackOpenShiftClient inside bootstrapReconcileOnce. Since nothing
+	// panics, no loop was started.
+	fakeClock.Step(10 * time.Second)
+	time.Sleep(50 * time.Millisecond)
+}
@@ -0,0 +1,59 @@
+package origin
+
+// BootstrapResult summarizes what EnsureBootstrapPolicy actually did, so upgrade tooling and diagnostics can
+// inspect bootstrap completeness (for example to drive an operator status condition) without scraping log
+// lines.
+type BootstrapResult struct {
+	// Steps holds the per-step outcome, keyed by the same step name used for the ensure_duration_seconds and
+	// ensure_errors_total metrics (see bootstrap_metrics.go), for example "scc" or "component_authz".
+	Steps map[string]*BootstrapStepResult
+}
+
+// BootstrapStepResult captures the outcome of a single bootstrap ensure step. Counts is keyed by whatever
+// categories are meaningful for that step - for example "created"/"skipped"/"reconciled" for the SCC step,
+// or "rolesReconciled"/"roleBindingsReconciled" for the component authorization step - rather than a fixed
+// set of fields, since the steps don't all produce the same kind of outcome.
+type BootstrapStepResult struct {
+	Counts map[string]int
+	// Names records object names against categories, for steps where a summary needs to name the specific
+	// objects involved rather than just a count - for example the SCC step's "created" and "existing" bootstrap
+	// SCC names. Nil unless a step explicitly calls setNames; most steps only populate Counts.
+	Names  map[string][]string
+	Errors []error
+}
+
+// newBootstrapResult returns an empty BootstrapResult ready to have steps recorded into it.
+func newBootstrapResult() *BootstrapResult {
+	return &BootstrapResult{Steps: map[string]*BootstrapStepResult{}}
+}
+
+// step returns the named step's result, creating it on first access so callers never have to nil-check.
+func (r *BootstrapResult) step(name string) *BootstrapStepResult {
+	step, ok := r.Steps[name]
+	if !ok {
+		step = &BootstrapStepResult{Counts: map[string]int{}}
+		r.Steps[name] = step
+	}
+	return step
+}
+
+// inc increments the named count by one.
+func (s *BootstrapStepResult) inc(category string) {
+	s.Counts[category]++
+}
+
+// addError records a non-nil error against the step without interrupting it, mirroring the existing
+// best-effort, log-and-continue behavior of the ensure* steps.
+func (s *BootstrapStepResult) addError(err error) {
+	if err != nil {
+		s.Errors = append(s.Errors, err)
+	}
+}
+
+// setNames records names under category, lazily allocating Names on first use.
+func (s *BootstrapStepResult) setNames(category string, names []string) {
+	if s.Names == nil {
+		s.Names = map[string][]string{}
+	}
+	s.Names[category] = names
+}
@@ -0,0 +1,49 @@
+package origin
+
+import (
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// BootstrapStepOutcome classifies how a single EnsureBootstrapPolicy step concluded, so a
+// BootstrapStepCallback doesn't have to infer the outcome from a nil/non-nil error.
+type BootstrapStepOutcome string
+
+const (
+	// BootstrapStepOutcomeSuccess means the step ran and recorded no errors.
+	BootstrapStepOutcomeSuccess BootstrapStepOutcome = "success"
+	// BootstrapStepOutcomeFailure means the step ran but returned or recorded at least one error, regardless
+	// of whether its StepFailurePolicy was Required or Optional.
+	BootstrapStepOutcomeFailure BootstrapStepOutcome = "failure"
+	// BootstrapStepOutcomeSkipped means the step did not run this time: it was disabled via
+	// BootstrapOptions.DisabledSteps, or a prior run already recorded it complete (see
+	// skipAlreadyCompletedBootstrapStep).
+	BootstrapStepOutcomeSkipped BootstrapStepOutcome = "skipped"
+)
+
+// BootstrapStepCallback is invoked once per EnsureBootstrapPolicy step with the step's name (the same name
+// used for the ensure_duration_seconds/ensure_errors_total metrics, e.g. "scc" or "component_authz"), its
+// outcome, and - for BootstrapStepOutcomeFailure - the error responsible. err is nil for every other outcome.
+type BootstrapStepCallback func(step string, outcome BootstrapStepOutcome, err error)
+
+// reportBootstrapStep invokes the configured BootstrapStepCallback, if any, with step's outcome. Left unset,
+// this is a no-op, so deployments that don't need the hook pay nothing beyond the nil check.
+func (c *MasterConfig) reportBootstrapStep(step string, outcome BootstrapStepOutcome, err error) {
+	if c.BootstrapStepCallback == nil {
+		return
+	}
+	c.BootstrapStepCallback(step, outcome, err)
+}
+
+// bootstrapStepOutcome derives the BootstrapStepOutcome and reportable error for a finished step from its
+// direct error (stepErr, e.g. a timeout) together with any errors the step recorded on stepResult along the
+// way (see BootstrapStepResult.addError) - most ensure* steps record and continue rather than returning early,
+// so looking at stepErr alone would miss failures a step already swallowed for its own purposes.
+func bootstrapStepOutcome(stepErr error, stepResult *BootstrapStepResult) (BootstrapStepOutcome, error) {
+	if stepErr != nil {
+		return BootstrapStepOutcomeFailure, stepErr
+	}
+	if len(stepResult.Errors) > 0 {
+		return BootstrapStepOutcomeFailure, utilerrors.NewAggregate(stepResult.Errors)
+	}
+	return BootstrapStepOutcomeSuccess, nil
+}
@@ -0,0 +1,59 @@
+package origin
+
+// BootstrapTracer is the tracing interface used to wrap the ensure* bootstrap steps. It's deliberately
+// modeled after the OpenTracing/OpenTelemetry Tracer/Span shape (StartSpan returning a Span with SetTag and
+// Finish) so a deployment that already has an OpenTracing or OpenTelemetry tracer in process can adapt it to
+// this interface in a few lines, without this package taking on either as a dependency. Left nil, bootstrap
+// tracing is a no-op and costs nothing.
+type BootstrapTracer interface {
+	// StartSpan starts and returns a new BootstrapSpan named name. The caller is responsible for calling
+	// Finish on the returned span exactly once.
+	StartSpan(name string) BootstrapSpan
+}
+
+// BootstrapSpan is a single traced operation, for example one ensure* bootstrap step.
+type BootstrapSpan interface {
+	// SetTag attaches a key/value attribute to the span, for example step name, namespace, or error status.
+	SetTag(key string, value interface{})
+	// Finish marks the span complete.
+	Finish()
+}
+
+// noopBootstrapTracer is the default BootstrapTracer, used whenever MasterConfig.BootstrapTracer is nil so
+// callers never need to nil-check before starting a span.
+type noopBootstrapTracer struct{}
+
+func (noopBootstrapTracer) StartSpan(name string) BootstrapSpan { return noopBootstrapSpan{} }
+
+type noopBootstrapSpan struct{}
+
+func (noopBootstrapSpan) SetTag(key string, value interface{}) {}
+func (noopBootstrapSpan) Finish()                              {}
+
+// bootstrapTracer returns the configured BootstrapTracer, falling back to a no-op tracer so callers always
+// have one to start spans on.
+func (c *MasterConfig) bootstrapTracer() BootstrapTracer {
+	if c.BootstrapTracer != nil {
+		return c.BootstrapTracer
+	}
+	return noopBootstrapTracer{}
+}
+
+// traceBootstrapStep runs fn inside a span named step, tagging it with "step" and any extra tags, and
+// "error"=true if fn returns a non-nil error. It's used to wrap each EnsureBootstrapPolicy step so a
+// deployment with tracing wired up can see exactly which bootstrap operation dominates startup latency.
+func (c *MasterConfig) traceBootstrapStep(step string, tags map[string]interface{}, fn func() error) error {
+	span := c.bootstrapTracer().StartSpan(step)
+	defer span.Finish()
+
+	span.SetTag("step", step)
+	for key, value := range tags {
+		span.SetTag(key, value)
+	}
+
+	err := fn()
+	if err != nil {
+		span.SetTag("error", true)
+	}
+	return err
+}
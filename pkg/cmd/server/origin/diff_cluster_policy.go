@@ -0,0 +1,101 @@
+package origin
+
+import (
+	"reflect"
+
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/apis/authorization"
+	"github.com/openshift/origin/pkg/cmd/server/bootstrappolicy"
+)
+
+// PolicyDiff summarizes how the cluster's stored policy differs from what this binary would bootstrap. It's
+// produced by DiffClusterPolicy to back an "upgrade preflight" report, so an admin can see what
+// EnsureBootstrapPolicy would change before letting it run.
+type PolicyDiff struct {
+	// AddedRoles and AddedBindings name cluster roles/rolebindings this binary would create that don't exist
+	// in the cluster yet.
+	AddedRoles    []string
+	AddedBindings []string
+	// RemovedRoles and RemovedBindings name cluster roles/rolebindings that exist in the cluster but this
+	// binary no longer bootstraps - for example ones dropped in a later release.
+	RemovedRoles    []string
+	RemovedBindings []string
+	// ChangedRoles and ChangedBindings name cluster roles/rolebindings that exist in both but whose
+	// rules/subjects no longer match what this binary would bootstrap.
+	ChangedRoles    []string
+	ChangedBindings []string
+}
+
+// DiffClusterPolicy compares the cluster policy currently stored via the cluster policy registry, and the
+// cluster role bindings currently on the API, against what this binary's bootstrap policy would produce. It
+// never creates, updates, or deletes anything - it only reads - so it's safe to run against a live cluster
+// ahead of an upgrade to see what EnsureBootstrapPolicy would change.
+func (c *MasterConfig) DiffClusterPolicy() (*PolicyDiff, error) {
+	clusterPolicyRegistry, err := c.clusterPolicyRegistryLazy()
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+	actualPolicy, err := clusterPolicyRegistry.GetClusterPolicy(reqCtx, authorizationapi.PolicyName, &metav1.GetOptions{})
+	if err != nil && !kapierror.IsNotFound(err) {
+		return nil, err
+	}
+	actualRoles := authorizationapi.ClusterRolesByName{}
+	if actualPolicy != nil {
+		actualRoles = actualPolicy.Roles
+	}
+
+	actualBindingList, err := c.PrivilegedLoopbackOpenShiftClient.ClusterRoleBindings().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	actualBindings := map[string]authorizationapi.ClusterRoleBinding{}
+	for _, binding := range actualBindingList.Items {
+		actualBindings[binding.Name] = binding
+	}
+
+	diff := &PolicyDiff{}
+
+	desiredRoleNames := sets.NewString()
+	for _, role := range bootstrappolicy.GetBootstrapClusterRoles() {
+		desiredRoleNames.Insert(role.Name)
+		existing, ok := actualRoles[role.Name]
+		if !ok {
+			diff.AddedRoles = append(diff.AddedRoles, role.Name)
+			continue
+		}
+		if !reflect.DeepEqual(existing.Rules, role.Rules) {
+			diff.ChangedRoles = append(diff.ChangedRoles, role.Name)
+		}
+	}
+	for name := range actualRoles {
+		if !desiredRoleNames.Has(name) {
+			diff.RemovedRoles = append(diff.RemovedRoles, name)
+		}
+	}
+
+	desiredBindingNames := sets.NewString()
+	for _, binding := range bootstrappolicy.GetBootstrapClusterRoleBindings() {
+		desiredBindingNames.Insert(binding.Name)
+		existing, ok := actualBindings[binding.Name]
+		if !ok {
+			diff.AddedBindings = append(diff.AddedBindings, binding.Name)
+			continue
+		}
+		if existing.RoleRef != binding.RoleRef || !reflect.DeepEqual(existing.Subjects, binding.Subjects) {
+			diff.ChangedBindings = append(diff.ChangedBindings, binding.Name)
+		}
+	}
+	for name := range actualBindings {
+		if !desiredBindingNames.Has(name) {
+			diff.RemovedBindings = append(diff.RemovedBindings, name)
+		}
+	}
+
+	return diff, nil
+}
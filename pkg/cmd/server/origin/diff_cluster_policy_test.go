@@ -0,0 +1,104 @@
+package origin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	restclient "k8s.io/client-go/rest"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/apis/authorization"
+	osclient "github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/cmd/server/bootstrappolicy"
+)
+
+// staleClusterPolicyRegistry is a minimal clusterpolicyregistry.Registry returning a fixed, already-stale
+// ClusterPolicy, for exercising DiffClusterPolicy without a real etcd-backed registry.
+type staleClusterPolicyRegistry struct {
+	policy *authorizationapi.ClusterPolicy
+}
+
+func (r staleClusterPolicyRegistry) ListClusterPolicies(ctx apirequest.Context, options *metainternal.ListOptions) (*authorizationapi.ClusterPolicyList, error) {
+	return nil, nil
+}
+func (r staleClusterPolicyRegistry) GetClusterPolicy(ctx apirequest.Context, id string, options *metav1.GetOptions) (*authorizationapi.ClusterPolicy, error) {
+	return r.policy, nil
+}
+func (r staleClusterPolicyRegistry) CreateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r staleClusterPolicyRegistry) UpdateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r staleClusterPolicyRegistry) DeleteClusterPolicy(ctx apirequest.Context, id string) error {
+	return nil
+}
+
+func TestDiffClusterPolicyReportsAddedRemovedAndChangedRoles(t *testing.T) {
+	desiredRoles := bootstrappolicy.GetBootstrapClusterRoles()
+	if len(desiredRoles) < 2 {
+		t.Fatalf("expected at least two bootstrap cluster roles to exercise this test, got %d", len(desiredRoles))
+	}
+	changedRole := desiredRoles[0]
+	// desiredRoles[1..] are intentionally left out of actualRoles below, so they surface as added.
+
+	actualRoles := authorizationapi.ClusterRolesByName{
+		changedRole.Name: {
+			ObjectMeta: metav1.ObjectMeta{Name: changedRole.Name},
+			// Deliberately stale: no rules, so it never matches the bootstrap definition.
+			Rules: nil,
+		},
+		"a-role-this-binary-no-longer-bootstraps": {
+			ObjectMeta: metav1.ObjectMeta{Name: "a-role-this-binary-no-longer-bootstraps"},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"ClusterRoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		clusterPolicyRegistry: staleClusterPolicyRegistry{
+			policy: &authorizationapi.ClusterPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: authorizationapi.PolicyName},
+				Roles:      actualRoles,
+			},
+		},
+	}
+
+	diff, err := c.DiffClusterPolicy()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(diff.AddedRoles) != len(desiredRoles)-1 {
+		t.Errorf("expected %d added roles, got %d: %v", len(desiredRoles)-1, len(diff.AddedRoles), diff.AddedRoles)
+	}
+	if len(diff.ChangedRoles) != 1 || diff.ChangedRoles[0] != changedRole.Name {
+		t.Errorf("expected %q to be reported as changed, got %v", changedRole.Name, diff.ChangedRoles)
+	}
+	if len(diff.RemovedRoles) != 1 || diff.RemovedRoles[0] != "a-role-this-binary-no-longer-bootstraps" {
+		t.Errorf("expected the extra actual role to be reported as removed, got %v", diff.RemovedRoles)
+	}
+
+	desiredBindings := bootstrappolicy.GetBootstrapClusterRoleBindings()
+	if len(diff.AddedBindings) != len(desiredBindings) {
+		t.Errorf("expected every bootstrap binding to be reported as added against an empty cluster, got %d of %d", len(diff.AddedBindings), len(desiredBindings))
+	}
+	if len(diff.RemovedBindings) != 0 || len(diff.ChangedBindings) != 0 {
+		t.Errorf("expected no removed or changed bindings, got removed=%v changed=%v", diff.RemovedBindings, diff.ChangedBindings)
+	}
+}
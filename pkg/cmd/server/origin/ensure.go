@@ -1,247 +1,3564 @@
 package origin
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/golang/glog"
 
 	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilwait "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/dynamic"
 	kapi "k8s.io/kubernetes/pkg/api"
+	kvalidation "k8s.io/kubernetes/pkg/api/validation"
 	"k8s.io/kubernetes/pkg/apis/rbac"
+	kclientsetinternal "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	rbacclient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/typed/rbac/internalversion"
 	"k8s.io/kubernetes/pkg/client/retry"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
 	kbootstrappolicy "k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac/bootstrappolicy"
 
 	"github.com/openshift/origin/pkg/oc/admin/policy"
 
+	osclient "github.com/openshift/origin/pkg/client"
+
 	authorizationapi "github.com/openshift/origin/pkg/authorization/apis/authorization"
 	clusterpolicyregistry "github.com/openshift/origin/pkg/authorization/registry/clusterpolicy"
-	clusterpolicystorage "github.com/openshift/origin/pkg/authorization/registry/clusterpolicy/etcd"
 	"github.com/openshift/origin/pkg/cmd/server/admin"
+	configapi "github.com/openshift/origin/pkg/cmd/server/api"
 	"github.com/openshift/origin/pkg/cmd/server/bootstrappolicy"
+	securityapi "github.com/openshift/origin/pkg/security/apis/security"
+	securityvalidation "github.com/openshift/origin/pkg/security/apis/security/validation"
 	"github.com/openshift/origin/pkg/security/legacyclient"
+	templateapi "github.com/openshift/origin/pkg/template/apis/template"
+	"github.com/openshift/origin/pkg/util/restoptions"
+	"github.com/openshift/origin/pkg/version"
 )
 
-// ensureOpenShiftSharedResourcesNamespace is called as part of global policy initialization to ensure shared namespace exists
-func (c *MasterConfig) ensureOpenShiftSharedResourcesNamespace() {
-	if _, err := c.KubeClientsetInternal().Core().Namespaces().Get(c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace, metav1.GetOptions{}); kapierror.IsNotFound(err) {
-		namespace, createErr := c.KubeClientsetInternal().Core().Namespaces().Create(&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace}})
-		if createErr != nil {
-			glog.Errorf("Error creating namespace: %v due to %v\n", c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace, createErr)
-			return
-		}
+// defaultBootstrapRetryBackoff mirrors the current behavior of a handful of quick retries and is used whenever
+// PolicyConfig.Bootstrap.RetryBackoff is left at its zero value.
+var defaultBootstrapRetryBackoff = utilwait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   2,
+	Steps:    4,
+}
 
-		c.ensureNamespaceServiceAccountRoleBindings(namespace)
+// bootstrapRetryBackoff returns the configured backoff for retrying transient bootstrap namespace creation
+// failures, falling back to defaultBootstrapRetryBackoff when unset.
+func (c *MasterConfig) bootstrapRetryBackoff() utilwait.Backoff {
+	backoff := c.Options.PolicyConfig.Bootstrap.RetryBackoff
+	if backoff.InitialIntervalSeconds == 0 && backoff.Factor == 0 && backoff.Steps == 0 {
+		return defaultBootstrapRetryBackoff
+	}
+	return utilwait.Backoff{
+		Duration: time.Duration(backoff.InitialIntervalSeconds) * time.Second,
+		Factor:   backoff.Factor,
+		Jitter:   backoff.Jitter,
+		Steps:    backoff.Steps,
 	}
 }
 
-// ensureOpenShiftInfraNamespace is called as part of global policy initialization to ensure infra namespace exists
-func (c *MasterConfig) ensureOpenShiftInfraNamespace() {
-	ns := c.Options.PolicyConfig.OpenShiftInfrastructureNamespace
+// defaultBootstrapStepTimeout bounds how long a single EnsureBootstrapPolicy step may run before it's
+// abandoned, and is used whenever PolicyConfig.Bootstrap.StepTimeoutSeconds is left at its zero value.
+const defaultBootstrapStepTimeout = 2 * time.Minute
 
-	// Ensure namespace exists
-	namespace, err := c.KubeClientsetInternal().Core().Namespaces().Create(&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
-	if kapierror.IsAlreadyExists(err) {
-		// Get the persisted namespace
-		namespace, err = c.KubeClientsetInternal().Core().Namespaces().Get(ns, metav1.GetOptions{})
+// defaultBootstrapReadinessTimeout bounds how long EnsureBootstrapPolicy waits for the apiserver to answer a
+// readiness probe before giving up, and is used whenever PolicyConfig.Bootstrap.ReadinessTimeoutSeconds is
+// left at its zero value.
+const defaultBootstrapReadinessTimeout = 30 * time.Second
+
+// bootstrapReadinessProbeInterval is how often waitForAPIServerReady retries its readiness probe.
+const bootstrapReadinessProbeInterval = time.Second
+
+// bootstrapReadinessTimeout returns the configured bound on how long EnsureBootstrapPolicy waits for the
+// apiserver to become ready before running its first step, falling back to defaultBootstrapReadinessTimeout
+// when unset.
+func (c *MasterConfig) bootstrapReadinessTimeout() time.Duration {
+	if seconds := c.Options.PolicyConfig.Bootstrap.ReadinessTimeoutSeconds; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultBootstrapReadinessTimeout
+}
+
+// waitForAPIServerReady polls a cheap read - listing namespaces with a limit of 1 - until it succeeds or
+// bootstrapReadinessTimeout elapses, so EnsureBootstrapPolicy's first step doesn't run against an apiserver
+// that isn't done initializing yet during combined startup and spuriously fail. ctx is checked on every poll,
+// so a context canceled while waiting stops the probe immediately instead of running out the full timeout.
+func (c *MasterConfig) waitForAPIServerReady(ctx context.Context) error {
+	var lastErr error
+	pollErr := utilwait.PollImmediate(bootstrapReadinessProbeInterval, c.bootstrapReadinessTimeout(), func() (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		_, err := c.KubeClientsetInternal().Core().Namespaces().List(metav1.ListOptions{Limit: 1})
 		if err != nil {
-			glog.Errorf("Error getting namespace %s: %v", ns, err)
-			return
+			lastErr = err
+			return false, nil
 		}
-	} else if err != nil {
-		glog.Errorf("Error creating namespace %s: %v", ns, err)
-		return
+		return true, nil
+	})
+	if pollErr == nil {
+		return nil
 	}
+	if pollErr == utilwait.ErrWaitTimeout && lastErr != nil {
+		return fmt.Errorf("apiserver was not ready within %s: %v", c.bootstrapReadinessTimeout(), lastErr)
+	}
+	return pollErr
+}
+
+// defaultInfraNamespaceTerminatingWait bounds how long ensureOpenShiftInfraNamespace waits for the infra
+// namespace to leave the Terminating phase - left behind by a very recent `oc delete namespace` racing with
+// this bootstrap run's own re-creation - before giving up, and is used whenever
+// PolicyConfig.Bootstrap.InfraNamespaceTerminatingWaitSeconds is left at its zero value.
+const defaultInfraNamespaceTerminatingWait = 30 * time.Second
+
+// bootstrapStepTimeout returns the configured per-step deadline for EnsureBootstrapPolicy steps, falling back
+// to defaultBootstrapStepTimeout when unset.
+func (c *MasterConfig) bootstrapStepTimeout() time.Duration {
+	if seconds := c.Options.PolicyConfig.Bootstrap.StepTimeoutSeconds; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultBootstrapStepTimeout
+}
+
+// infraNamespaceTerminatingWait returns the configured bound on how long to wait for a Terminating infra
+// namespace to clear, falling back to defaultInfraNamespaceTerminatingWait when unset.
+func (c *MasterConfig) infraNamespaceTerminatingWait() time.Duration {
+	if seconds := c.Options.PolicyConfig.Bootstrap.InfraNamespaceTerminatingWaitSeconds; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultInfraNamespaceTerminatingWait
+}
+
+// waitForInfraNamespaceActive polls the infra namespace until it's no longer Terminating, bounded by
+// infraNamespaceTerminatingWait. A Terminating namespace here almost always means a prior delete raced with
+// this bootstrap run's own re-creation; namespace deletes typically clear in seconds, so a short bounded poll
+// avoids racing role reconciliation against a namespace that's about to disappear out from under it. If
+// namespace isn't Terminating, it's returned unchanged. Returns a descriptive error if the namespace never
+// clears the Terminating phase within the bound.
+func (c *MasterConfig) waitForInfraNamespaceActive(ns string, namespace *kapi.Namespace) (*kapi.Namespace, error) {
+	if namespace.Status.Phase != kapi.NamespaceTerminating {
+		return namespace, nil
+	}
+	c.bootstrapLog().Info("Infra namespace is Terminating; waiting for it to clear before reconciling", "namespace", ns)
 
-	for _, role := range bootstrappolicy.ControllerRoles() {
-		reconcileRole := &policy.ReconcileClusterRolesOptions{
-			RolesToReconcile: []string{role.Name},
-			Confirmed:        true,
-			Union:            true,
-			Out:              ioutil.Discard,
-			RoleClient:       c.PrivilegedLoopbackOpenShiftClient.ClusterRoles(),
+	current := namespace
+	pollErr := utilwait.PollImmediate(time.Second, c.infraNamespaceTerminatingWait(), func() (bool, error) {
+		var getErr error
+		current, getErr = c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+		if getErr != nil {
+			return false, getErr
 		}
-		if err := reconcileRole.RunReconcileClusterRoles(nil, nil); err != nil {
-			glog.Errorf("Could not reconcile %v: %v\n", role.Name, err)
+		return current.Status.Phase != kapi.NamespaceTerminating, nil
+	})
+	if pollErr != nil {
+		return current, fmt.Errorf("infra namespace %q did not leave the Terminating phase within %s: %v", ns, c.infraNamespaceTerminatingWait(), pollErr)
+	}
+	c.bootstrapLog().Info("Infra namespace left the Terminating phase", "namespace", ns, "phase", current.Status.Phase)
+	return current, nil
+}
+
+// runBootstrapStepWithTimeout runs fn under a deadline of bootstrapStepTimeout, so a step blocked on a slow or
+// wedged apiserver/etcd call - GetClusterPolicy, an SCC create, and the like - can't hang master startup
+// indefinitely. fn is started in its own goroutine and given stepCtx, which honors the deadline; if fn hasn't
+// returned by the deadline, a context.DeadlineExceeded error is recorded against result and returned, and fn
+// is abandoned rather than force-killed (the ensure* steps are idempotent and safe to retry on the next master
+// restart, so a still-running abandoned attempt finishing later is harmless).
+func (c *MasterConfig) runBootstrapStepWithTimeout(ctx context.Context, result *BootstrapStepResult, fn func(stepCtx context.Context) error) error {
+	stepCtx, cancel := context.WithTimeout(ctx, c.bootstrapStepTimeout())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn(stepCtx)
+	}()
+
+	select {
+	case err := <-errCh:
+		// fn is responsible for recording its own errors against result; only the deadline-exceeded case
+		// below is the wrapper's own error to record.
+		return err
+	case <-stepCtx.Done():
+		err := stepCtx.Err()
+		c.bootstrapLog().Error(err, "Bootstrap step exceeded its deadline; abandoning")
+		result.addError(err)
+		return err
+	}
+}
+
+// enforceBootstrapStepFailurePolicy applies step's configured Required/Optional classification (see
+// BootstrapOptions.StepFailurePolicy) once step has finished: a Required step that returned stepErr, or that
+// recorded any errors on stepResult, aborts EnsureBootstrapPolicy by returning that failure; an Optional step's
+// failure is left recorded on stepResult and EnsureBootstrapPolicy continues on to the next step.
+func (c *MasterConfig) enforceBootstrapStepFailurePolicy(step string, stepResult *BootstrapStepResult, stepErr error) error {
+	if stepErr == nil && len(stepResult.Errors) == 0 {
+		return nil
+	}
+	if loopbackErr := findLoopbackUnauthorizedError(stepErr, stepResult.Errors); loopbackErr != nil {
+		return loopbackErr
+	}
+	if c.Options.PolicyConfig.Bootstrap.StepFailurePolicy(step) != configapi.BootstrapStepRequired {
+		return nil
+	}
+	if stepErr != nil {
+		return stepErr
+	}
+	return utilerrors.NewAggregate(stepResult.Errors)
+}
+
+// findLoopbackUnauthorizedError reports whether stepErr or any error in stepErrors is an
+// *ErrLoopbackUnauthorized, returning the first one found or nil if none is. Checked ahead of a step's
+// Required/Optional StepFailurePolicy, since a misconfigured loopback client will keep failing every
+// remaining step the same way regardless of that policy - EnsureBootstrapPolicy should short-circuit on the
+// clear diagnosis instead of limping through the rest.
+func findLoopbackUnauthorizedError(stepErr error, stepErrors []error) *ErrLoopbackUnauthorized {
+	if loopbackErr, ok := stepErr.(*ErrLoopbackUnauthorized); ok {
+		return loopbackErr
+	}
+	for _, err := range stepErrors {
+		if loopbackErr, ok := err.(*ErrLoopbackUnauthorized); ok {
+			return loopbackErr
 		}
 	}
-	for _, roleBinding := range bootstrappolicy.ControllerRoleBindings() {
-		reconcileRoleBinding := &policy.ReconcileClusterRoleBindingsOptions{
-			RolesToReconcile:  []string{roleBinding.RoleRef.Name},
-			Confirmed:         true,
-			Union:             true,
-			Out:               ioutil.Discard,
-			RoleBindingClient: c.PrivilegedLoopbackOpenShiftClient.ClusterRoleBindings(),
+	return nil
+}
+
+// skipDisabledBootstrapStep reports whether step is listed in BootstrapOptions.DisabledSteps, and if so,
+// records "skipped" on its BootstrapStepResult, reports BootstrapStepOutcomeSkipped to BootstrapStepCallback,
+// and logs at info level. A disabled step never runs, so its StepFailurePolicy is never consulted - there's
+// nothing for it to fail.
+func (c *MasterConfig) skipDisabledBootstrapStep(step string, result *BootstrapResult) bool {
+	if !c.Options.PolicyConfig.Bootstrap.IsStepDisabled(step) {
+		return false
+	}
+	c.bootstrapLog().Info("Bootstrap step disabled; skipping", "step", step)
+	result.step(step).inc("skipped")
+	c.reportBootstrapStep(step, BootstrapStepOutcomeSkipped, nil)
+	return true
+}
+
+// skipAlreadyCompletedBootstrapStep reports whether step was already recorded complete by
+// recordBootstrapStepComplete on a prior EnsureBootstrapPolicy run that failed on a later step, and if so,
+// records "skipped" on its BootstrapStepResult and reports BootstrapStepOutcomeSkipped to
+// BootstrapStepCallback, the same way skipDisabledBootstrapStep does. This lets a restart after a mid-bootstrap
+// failure resume just past whatever step it previously got through instead of redoing every step from scratch.
+// MasterConfig.ForceFullBootstrap bypasses this unconditionally, same as it does for bootstrapUpToDate.
+func (c *MasterConfig) skipAlreadyCompletedBootstrapStep(step string, result *BootstrapResult) bool {
+	if c.ForceFullBootstrap || !c.bootstrapStepAlreadyCompleted(step) {
+		return false
+	}
+	c.bootstrapLog().Info("Bootstrap step already completed on a prior run; resuming past it", "step", step)
+	result.step(step).inc("skipped")
+	c.reportBootstrapStep(step, BootstrapStepOutcomeSkipped, nil)
+	return true
+}
+
+// defaultServiceAccountRoleRetryBackoff matches retry.DefaultRetry, jitter factor included, and
+// is used whenever PolicyConfig.Bootstrap.ServiceAccountRoleRetryBackoff is left at its zero value.
+var defaultServiceAccountRoleRetryBackoff = utilwait.Backoff{
+	Duration: 10 * time.Millisecond,
+	Factor:   1.0,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// serviceAccountRoleRetryBackoff returns the configured backoff for retrying conflicts while initializing a
+// namespace's service account role bindings, falling back to defaultServiceAccountRoleRetryBackoff when unset.
+// Unlike bootstrapRetryBackoff, the fallback always jitters, since this retry runs once per namespace and many
+// namespaces initializing together (for example after a mass project creation or upgrade) would otherwise
+// retry conflicts against the apiserver in lockstep.
+func (c *MasterConfig) serviceAccountRoleRetryBackoff() utilwait.Backoff {
+	backoff := c.Options.PolicyConfig.Bootstrap.ServiceAccountRoleRetryBackoff
+	if backoff.InitialIntervalSeconds == 0 && backoff.Factor == 0 && backoff.Steps == 0 && backoff.Jitter == 0 {
+		return defaultServiceAccountRoleRetryBackoff
+	}
+	return utilwait.Backoff{
+		Duration: time.Duration(backoff.InitialIntervalSeconds) * time.Second,
+		Factor:   backoff.Factor,
+		Jitter:   backoff.Jitter,
+		Steps:    backoff.Steps,
+	}
+}
+
+// isTransientCreateError returns true for errors worth retrying (network errors and 5xx server errors), and
+// false for errors like IsAlreadyExists or permission failures that won't be fixed by trying again.
+func isTransientCreateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if kapierror.IsAlreadyExists(err) || kapierror.IsForbidden(err) || kapierror.IsInvalid(err) || kapierror.IsUnauthorized(err) {
+		return false
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	return kapierror.IsServerTimeout(err) || kapierror.IsTimeout(err) || kapierror.IsInternalError(err) || kapierror.IsServiceUnavailable(err) || kapierror.IsTooManyRequests(err)
+}
+
+// retryOnTransientError retries fn using the master's configured bootstrap backoff whenever it returns a
+// transient error, and returns immediately on success or a non-transient failure. It also bails out with
+// ctx.Err() as soon as ctx is cancelled or its deadline elapses, rather than continuing to retry.
+func (c *MasterConfig) retryOnTransientError(ctx context.Context, fn func() error) error {
+	var lastErr error
+	err := utilwait.ExponentialBackoff(c.bootstrapRetryBackoff(), func() (bool, error) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			lastErr = ctxErr
+			return false, ctxErr
+		}
+		lastErr = fn()
+		if lastErr == nil {
+			return true, nil
 		}
-		if err := reconcileRoleBinding.RunReconcileClusterRoleBindings(nil, nil); err != nil {
-			glog.Errorf("Could not reconcile %v: %v\n", roleBinding.Name, err)
+		if !isTransientCreateError(lastErr) {
+			return false, lastErr
 		}
+		return false, nil
+	})
+	if err != nil && err != lastErr {
+		// the backoff was exhausted rather than short-circuited by a non-transient error
+		return lastErr
 	}
+	return err
+}
 
-	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+// sharedResourcesNamespaces returns every namespace that should carry the shared templates and imagestreams:
+// the authoritative OpenShiftSharedResourcesNamespace followed by any AdditionalSharedResourcesNamespaces, in
+// that order.
+func (c *MasterConfig) sharedResourcesNamespaces() []string {
+	namespaces := []string{c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace}
+	return append(namespaces, c.Options.PolicyConfig.AdditionalSharedResourcesNamespaces...)
 }
 
-// ensureDefaultNamespaceServiceAccountRoles initializes roles for service accounts in the default namespace
-func (c *MasterConfig) ensureDefaultNamespaceServiceAccountRoles() {
-	// Wait for the default namespace
-	var namespace *kapi.Namespace
-	for i := 0; i < 30; i++ {
-		ns, err := c.KubeClientsetInternal().Core().Namespaces().Get(metav1.NamespaceDefault, metav1.GetOptions{})
-		if err == nil {
-			namespace = ns
-			break
+// validateSharedResourcesNamespaces returns an error if AdditionalSharedResourcesNamespaces collides with the
+// primary OpenShiftSharedResourcesNamespace, contains a duplicate, or contains the infra namespace - all of
+// which would produce confusing or destructive behavior when the namespaces are ensured.
+func (c *MasterConfig) validateSharedResourcesNamespaces() error {
+	seen := sets.NewString()
+	for _, ns := range c.sharedResourcesNamespaces() {
+		if ns == c.Options.PolicyConfig.OpenShiftInfrastructureNamespace {
+			return fmt.Errorf("shared resources namespace %q must not be the infra namespace", ns)
 		}
-		if kapierror.IsNotFound(err) {
-			time.Sleep(time.Second)
-			continue
+		if seen.Has(ns) {
+			return fmt.Errorf("shared resources namespace %q is listed more than once", ns)
 		}
-		glog.Errorf("Error adding service account roles to %q namespace: %v", metav1.NamespaceDefault, err)
-		return
+		seen.Insert(ns)
 	}
-	if namespace == nil {
-		glog.Errorf("Namespace %q not found, could not initialize the %q namespace", metav1.NamespaceDefault, metav1.NamespaceDefault)
-		return
+	return nil
+}
+
+// validateBootstrapNamespaceConfig checks that every PolicyConfig field the ensure* bootstrap methods depend
+// on to name a namespace or locate the bootstrap policy file is non-empty and, for namespace names, a
+// well-formed DNS label. Catching this up front turns what would otherwise be a confusing apiserver
+// validation error deep inside an ensure* method - or a namespace silently created with an empty name - into
+// a single, actionable error before EnsureBootstrapPolicy runs any step.
+func (c *MasterConfig) validateBootstrapNamespaceConfig() error {
+	errs := []error{}
+
+	validateNamespaceName := func(fieldName, value string) {
+		if len(value) == 0 {
+			errs = append(errs, fmt.Errorf("policyConfig.%s must not be empty", fieldName))
+			return
+		}
+		if reasons := kvalidation.ValidateNamespaceName(value, false); len(reasons) > 0 {
+			errs = append(errs, fmt.Errorf("policyConfig.%s %q is not a valid namespace name: %s", fieldName, value, strings.Join(reasons, ", ")))
+		}
 	}
 
-	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+	validateNamespaceName("openShiftInfrastructureNamespace", c.infraNamespace())
+	validateNamespaceName("openShiftSharedResourcesNamespace", c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace)
+	for i, ns := range c.Options.PolicyConfig.AdditionalSharedResourcesNamespaces {
+		validateNamespaceName(fmt.Sprintf("additionalSharedResourcesNamespaces[%d]", i), ns)
+	}
+
+	if len(c.Options.PolicyConfig.BootstrapPolicyFile) == 0 {
+		errs = append(errs, fmt.Errorf("policyConfig.bootstrapPolicyFile must not be empty"))
+	}
+
+	return utilerrors.NewAggregate(errs)
 }
 
-// ensureNamespaceServiceAccountRoleBindings initializes roles for service accounts in the namespace
-func (c *MasterConfig) ensureNamespaceServiceAccountRoleBindings(namespace *kapi.Namespace) {
-	const ServiceAccountRolesInitializedAnnotation = "openshift.io/sa.initialized-roles"
+// bootstrapNamespaceLabel marks a namespace as having been created by bootstrap policy initialization, so
+// cluster tooling can tell bootstrap-owned namespaces apart from user-created ones.
+const bootstrapNamespaceLabel = "openshift.io/bootstrap"
 
-	// Short-circuit if we're already initialized
-	if namespace.Annotations[ServiceAccountRolesInitializedAnnotation] == "true" {
-		return
+// bootstrapMasterVersionAnnotation records the GitVersion of the master that created or last stamped a
+// bootstrap namespace.
+const bootstrapMasterVersionAnnotation = "openshift.io/bootstrap-master-version"
+
+// bootstrapLastCompletedStepAnnotation records the name of the last EnsureBootstrapPolicy step (one of the
+// BootstrapStep* name constants) that completed successfully on the infra namespace, so a restart after a
+// mid-bootstrap failure can resume just past it instead of re-running every step from scratch. It's only
+// meaningful together with bootstrapLastCompletedStepVersionAnnotation - see lastCompletedBootstrapStep.
+const bootstrapLastCompletedStepAnnotation = "openshift.io/bootstrap-last-completed-step"
+
+// bootstrapLastCompletedStepVersionAnnotation records the GitVersion of the master that stamped
+// bootstrapLastCompletedStepAnnotation. lastCompletedBootstrapStep ignores the recorded step unless this
+// matches the running binary's version, so upgrading to a new version - which may add cluster roles, SCCs, or
+// bindings a resumed-past step would never create - re-runs every step instead of skipping straight past all
+// of them because "scc", the last entry in bootstrapStepOrder, was left over from the old version's completed
+// run.
+const bootstrapLastCompletedStepVersionAnnotation = "openshift.io/bootstrap-last-completed-step-version"
+
+// bootstrapStepOrder is the fixed sequence EnsureBootstrapPolicy runs its steps in. A step recorded by
+// recordBootstrapStepComplete implies every step before it in this order also completed, since steps only
+// ever run in this sequence.
+var bootstrapStepOrder = []string{
+	configapi.BootstrapStepComponentAuthz,
+	configapi.BootstrapStepInfraNamespace,
+	configapi.BootstrapStepSharedResourcesNamespace,
+	configapi.BootstrapStepDefaultNamespaceSARoles,
+	configapi.BootstrapStepSCC,
+}
+
+// bootstrapStepIndex returns step's position in bootstrapStepOrder, or -1 if step isn't recognized.
+func bootstrapStepIndex(step string) int {
+	for i, s := range bootstrapStepOrder {
+		if s == step {
+			return i
+		}
 	}
+	return -1
+}
 
-	hasErrors := false
-	for _, binding := range bootstrappolicy.GetBootstrapServiceAccountProjectRoleBindings(namespace.Name) {
-		addRole := &policy.RoleModificationOptions{
-			RoleName:            binding.RoleRef.Name,
-			RoleNamespace:       binding.RoleRef.Namespace,
-			RoleBindingAccessor: policy.NewLocalRoleBindingAccessor(namespace.Name, c.ServiceAccountRoleBindingClient()),
-			Subjects:            binding.Subjects,
+// lastCompletedBootstrapStep returns the step name recorded by recordBootstrapStepComplete for the currently
+// running binary's version, or "" if none is recorded, the infra namespace can't be read, or the record was
+// stamped by a different version - matching bootstrapUpToDate's policy of never skipping work on an
+// inconclusive read. Ignoring a stale version's record is what makes an upgrade re-run every step instead of
+// resuming past all of them; see bootstrapLastCompletedStepVersionAnnotation.
+func (c *MasterConfig) lastCompletedBootstrapStep() string {
+	namespace, err := c.bootstrapKubeClient().Core().Namespaces().Get(c.infraNamespace(), metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	if namespace.Annotations[bootstrapLastCompletedStepVersionAnnotation] != version.Get().String() {
+		return ""
+	}
+	return namespace.Annotations[bootstrapLastCompletedStepAnnotation]
+}
+
+// bootstrapStepAlreadyCompleted reports whether step - or a later step in bootstrapStepOrder - was already
+// recorded complete by a prior EnsureBootstrapPolicy run. Unrecognized steps are never treated as completed.
+func (c *MasterConfig) bootstrapStepAlreadyCompleted(step string) bool {
+	stepIndex := bootstrapStepIndex(step)
+	if stepIndex < 0 {
+		return false
+	}
+	completedIndex := bootstrapStepIndex(c.lastCompletedBootstrapStep())
+	return completedIndex >= stepIndex
+}
+
+// recordBootstrapStepComplete stamps the infra namespace with step as the last successfully completed
+// EnsureBootstrapPolicy step, retrying on conflict like the other annotation writers in this file, so a
+// restart after a mid-bootstrap failure knows exactly where to resume. Logs and swallows any other error:
+// failing to record progress only costs the next restart a redundant re-run of this step rather than leaving
+// anything mis-provisioned.
+func (c *MasterConfig) recordBootstrapStepComplete(step string) {
+	ns := c.infraNamespace()
+	currentVersion := version.Get().String()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		namespace, err := c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+		if err != nil {
+			return err
 		}
-		if err := retry.RetryOnConflict(retry.DefaultRetry, func() error { return addRole.AddRole() }); err != nil {
-			glog.Errorf("Could not add service accounts to the %v role in the %q namespace: %v\n", binding.RoleRef.Name, namespace.Name, err)
-			hasErrors = true
+		if namespace.Annotations[bootstrapLastCompletedStepAnnotation] == step && namespace.Annotations[bootstrapLastCompletedStepVersionAnnotation] == currentVersion {
+			return nil
+		}
+		if namespace.Annotations == nil {
+			namespace.Annotations = map[string]string{}
 		}
+		namespace.Annotations[bootstrapLastCompletedStepAnnotation] = step
+		namespace.Annotations[bootstrapLastCompletedStepVersionAnnotation] = currentVersion
+		_, err = c.bootstrapKubeClient().Core().Namespaces().Update(namespace)
+		return err
+	})
+	if err != nil {
+		c.bootstrapLog().Error(err, "Error recording completed bootstrap step on infra namespace", "namespace", ns, "step", step)
+		return
 	}
+	c.auditBootstrapMutation("namespace-annotate", ns)
+}
 
-	// If we had errors, don't register initialization so we can try again
-	if hasErrors {
+// defaultInfraNamespaceLabels are applied to the infra namespace, on top of bootstrapNamespaceLabels, when
+// PolicyConfig.InfraNamespaceLabels is unset, so monitoring auto-discovery can find it.
+var defaultInfraNamespaceLabels = map[string]string{"openshift.io/cluster-monitoring": "true"}
+
+// bootstrapNamespaceLabels returns the labels to stamp on a bootstrap-created namespace: the fixed
+// bootstrapNamespaceLabel marker, PolicyConfig.BootstrapNamespaceLabels, and any extra labels specific to
+// this particular namespace (for example the infra namespace's monitoring label). Later maps win on key
+// conflicts.
+func (c *MasterConfig) bootstrapNamespaceLabels(extra map[string]string) map[string]string {
+	labels := map[string]string{bootstrapNamespaceLabel: "true"}
+	for k, v := range c.Options.PolicyConfig.BootstrapNamespaceLabels {
+		labels[k] = v
+	}
+	for k, v := range extra {
+		labels[k] = v
+	}
+	return labels
+}
+
+// infraNamespaceLabels returns PolicyConfig.InfraNamespaceLabels, falling back to
+// defaultInfraNamespaceLabels when unset.
+func (c *MasterConfig) infraNamespaceLabels() map[string]string {
+	if c.Options.PolicyConfig.InfraNamespaceLabels != nil {
+		return c.Options.PolicyConfig.InfraNamespaceLabels
+	}
+	return defaultInfraNamespaceLabels
+}
+
+// infraNamespaceEnvVar, when set, overrides PolicyConfig.OpenShiftInfrastructureNamespace within the ensure*
+// bootstrap methods. It exists so integration tests can run multiple test masters against a shared apiserver
+// without their infra objects (namespace, SCCs, controller role bindings) colliding, without having to plumb
+// a distinct PolicyConfig through every test master. Production deployments never set it, so
+// c.Options.PolicyConfig.OpenShiftInfrastructureNamespace remains authoritative there.
+const infraNamespaceEnvVar = "OPENSHIFT_INFRA_NAMESPACE"
+
+// infraNamespace returns the effective infra namespace name: infraNamespaceEnvVar when set, otherwise
+// PolicyConfig.OpenShiftInfrastructureNamespace. Every ensure* method that names the infra namespace - and
+// the SCC user/group computation, which is keyed off of it - should call this instead of reading
+// PolicyConfig.OpenShiftInfrastructureNamespace directly.
+func (c *MasterConfig) infraNamespace() string {
+	if override := env(infraNamespaceEnvVar, ""); len(override) > 0 {
+		return override
+	}
+	return c.Options.PolicyConfig.OpenShiftInfrastructureNamespace
+}
+
+// applyNamespaceMutator applies the configured NamespaceMutator to namespace, if set. Left nil, this is a
+// no-op, so deployments that don't need the hook pay nothing beyond the nil check.
+func (c *MasterConfig) applyNamespaceMutator(namespace *kapi.Namespace) {
+	if c.NamespaceMutator == nil {
 		return
 	}
+	c.NamespaceMutator(namespace)
+}
 
+// stampBootstrapNamespaceMetadata applies labels and the creating master version annotation to namespace's
+// ObjectMeta.
+func stampBootstrapNamespaceMetadata(namespace *kapi.Namespace, labels map[string]string) {
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		namespace.Labels[k] = v
+	}
 	if namespace.Annotations == nil {
 		namespace.Annotations = map[string]string{}
 	}
-	namespace.Annotations[ServiceAccountRolesInitializedAnnotation] = "true"
-	// Log any error other than a conflict (the update will be retried and recorded again on next startup in that case)
-	if _, err := c.KubeClientsetInternal().Core().Namespaces().Update(namespace); err != nil && !kapierror.IsConflict(err) {
-		glog.Errorf("Error recording adding service account roles to %q namespace: %v", namespace.Name, err)
+	namespace.Annotations[bootstrapMasterVersionAnnotation] = version.Get().String()
+}
+
+// patchBootstrapNamespaceMetadata merges labels and the creating master version annotation into an existing
+// namespace, applies the configured NamespaceMutator, and updates it, so a namespace that predates this
+// bootstrap run still picks up the identifying metadata rather than being left unlabeled. The Update is
+// skipped only when nothing changed: no new labels and no NamespaceMutator configured to have possibly
+// touched some other field the label comparison wouldn't catch.
+func (c *MasterConfig) patchBootstrapNamespaceMetadata(namespace *kapi.Namespace, labels map[string]string) error {
+	before := map[string]string{}
+	for k, v := range namespace.Labels {
+		before[k] = v
+	}
+	stampBootstrapNamespaceMetadata(namespace, labels)
+	c.applyNamespaceMutator(namespace)
+	if c.NamespaceMutator == nil && reflect.DeepEqual(before, namespace.Labels) {
+		return nil
+	}
+
+	_, err := c.bootstrapKubeClient().Core().Namespaces().Update(namespace)
+	if err != nil {
+		c.bootstrapLog().Error(err, "Error patching bootstrap labels onto existing namespace", "namespace", namespace.Name)
 	}
+	return err
 }
 
-func (c *MasterConfig) ensureDefaultSecurityContextConstraints() {
-	ns := c.Options.PolicyConfig.OpenShiftInfrastructureNamespace
-	bootstrapSCCGroups, bootstrapSCCUsers := bootstrappolicy.GetBoostrapSCCAccess(ns)
+// ensureNamespaceExists creates ns if it doesn't exist yet and ensures its default service account role
+// bindings are in place, retrying transient failures with the master's configured backoff. This holds whether
+// ns was just created here or already existed - for example because an earlier master version created it, or
+// an admin created it by hand - since ensureNamespaceServiceAccountRoleBindings is itself idempotent via its
+// initialized-roles annotation short-circuit. Either way, ns ends up carrying the bootstrap namespace labels
+// and creating-master-version annotation, and any objects NamespaceCreationObjects returns for it. The
+// returned bool reports whether ns was newly created by this call, so a caller can gate one-time post-create
+// initialization on it rather than re-running it against a namespace that already existed.
+//
+// If BootstrapOptions.TolerateNamespaceCreationForbidden is set and Create returns Forbidden, that's treated
+// as informational rather than an error, on the assumption that some external process is responsible for
+// creating ns - supporting split-responsibility deployments where the loopback identity is intentionally not
+// granted namespace creation.
+func (c *MasterConfig) ensureNamespaceExists(ctx context.Context, ns string) (bool, error) {
+	existing, getErr := c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+	if getErr != nil && !kapierror.IsNotFound(getErr) {
+		c.bootstrapLog().Error(getErr, "Error getting namespace", "namespace", ns)
+		return false, getErr
+	}
+	if getErr == nil {
+		if err := c.patchBootstrapNamespaceMetadata(existing, c.bootstrapNamespaceLabels(nil)); err != nil {
+			return false, err
+		}
+		c.ensureNamespaceServiceAccountRoleBindings(existing)
+		if err := c.applyNamespaceCreationObjects(ns); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	toCreate := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+	stampBootstrapNamespaceMetadata(toCreate, c.bootstrapNamespaceLabels(nil))
+	c.applyNamespaceMutator(toCreate)
+
+	var namespace *kapi.Namespace
+	createErr := c.retryOnTransientError(ctx, func() error {
+		var createErr error
+		namespace, createErr = c.bootstrapKubeClient().Core().Namespaces().Create(toCreate)
+		return createErr
+	})
+	if createErr != nil {
+		if c.Options.PolicyConfig.Bootstrap.TolerateNamespaceCreationForbidden && kapierror.IsForbidden(createErr) {
+			c.bootstrapLog().Info("Not permitted to create namespace; assuming an external process will create it", "namespace", ns)
+			return false, nil
+		}
+		c.bootstrapLog().Error(createErr, "Error creating namespace", "namespace", ns)
+		return false, createErr
+	}
+	c.auditBootstrapMutation("namespace-create", ns)
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+	if err := c.applyNamespaceCreationObjects(ns); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// NamespaceObjectCreator creates a single object of kind gvk in namespace ns, returning nil without error if
+// an object of that name already exists there. It's the seam applyNamespaceCreationObjects uses to apply
+// whatever NamespaceCreationObjects returns, kept small and generic enough that tests can fake it without
+// standing up a real dynamic client against an apiserver.
+type NamespaceObjectCreator interface {
+	Create(ns string, gvk schema.GroupVersionKind, obj runtime.Object) error
+}
+
+// dynamicNamespaceObjectCreator is the default NamespaceObjectCreator, applying objects through a dynamic
+// client resolved against the master's own RESTMapper - the only way to create a type the master's compiled-in
+// scheme doesn't itself know about, such as a NetworkPolicy an operator's hook supplies.
+type dynamicNamespaceObjectCreator struct {
+	pool   dynamic.ClientPool
+	mapper meta.RESTMapper
+}
+
+func (d dynamicNamespaceObjectCreator) Create(ns string, gvk schema.GroupVersionKind, obj runtime.Object) error {
+	mapping, err := d.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("could not resolve %s to a REST resource: %v", gvk, err)
+	}
+
+	client, err := d.pool.ClientForGroupVersionResource(gvk.GroupVersion().WithResource(mapping.Resource))
+	if err != nil {
+		return fmt.Errorf("could not create a client for %s: %v", gvk, err)
+	}
 
-	for _, scc := range bootstrappolicy.GetBootstrapSecurityContextConstraints(bootstrapSCCGroups, bootstrapSCCUsers) {
-		_, err := legacyclient.NewFromClient(c.KubeClientsetInternal().Core().RESTClient()).Create(&scc)
-		if kapierror.IsAlreadyExists(err) {
+	unstructuredObj, err := toUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("could not convert %s to unstructured: %v", gvk, err)
+	}
+	unstructuredObj.SetNamespace(ns)
+
+	resourceClient := client.Resource(&metav1.APIResource{
+		Name:       mapping.Resource,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+		Kind:       gvk.Kind,
+	}, ns)
+
+	if _, err := resourceClient.Create(unstructuredObj); err != nil && !kapierror.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// namespaceObjectCreator returns the configured NamespaceObjectCreator, falling back to a
+// dynamicNamespaceObjectCreator built from the privileged loopback client config and the master's own
+// RESTMapper so callers always have one to use.
+func (c *MasterConfig) namespaceObjectCreator() NamespaceObjectCreator {
+	if c.NamespaceObjectCreator != nil {
+		return c.NamespaceObjectCreator
+	}
+	return dynamicNamespaceObjectCreator{
+		pool:   dynamic.NewDynamicClientPool(&c.PrivilegedLoopbackClientConfig),
+		mapper: kapi.Registry.RESTMapper(),
+	}
+}
+
+// applyNamespaceCreationObjects creates every object namespaceCreationObjects returns for ns. An object that
+// already exists is left alone rather than erred on, so the hook can be invoked unconditionally on every
+// bootstrap run without caring whether ns was just created or already existed. Errors creating any other
+// object are collected into a single aggregate rather than aborting on the first failure, so one bad object
+// doesn't prevent the rest from being applied.
+func (c *MasterConfig) applyNamespaceCreationObjects(ns string) error {
+	objects := c.namespaceCreationObjects(ns)
+	if len(objects) == 0 {
+		return nil
+	}
+
+	creator := c.namespaceObjectCreator()
+	errs := []error{}
+	for _, obj := range objects {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if err := creator.Create(ns, gvk, obj); err != nil {
+			c.bootstrapLog().Error(err, "Could not create namespace creation hook object", "namespace", ns, "kind", gvk)
+			errs = append(errs, err)
 			continue
 		}
+		c.bootstrapLog().Info("Created namespace creation hook object", "namespace", ns, "kind", gvk)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// toUnstructured round-trips obj through JSON to produce an *unstructured.Unstructured, so
+// dynamicNamespaceObjectCreator can hand it to a dynamic client without the master's scheme knowing obj's
+// concrete Go type. obj is returned as-is if it's already unstructured.
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// EnsureOpenShiftSharedResourcesNamespace is called as part of global policy initialization to ensure the shared
+// resources namespaces exist. Unlike ensureOpenShiftSharedResourcesNamespace, it returns an aggregated error so
+// callers can inspect what went wrong and optionally fail master startup on it. ctx is honored by the
+// underlying retry loop so a shutting-down master can cancel out of an in-flight retry instead of blocking it.
+// The returned bool reports whether any of the shared resources namespaces were newly created by this call, so
+// a caller can gate one-time post-create initialization on it rather than re-running it every time bootstrap
+// finds the namespaces already in place.
+func (c *MasterConfig) EnsureOpenShiftSharedResourcesNamespace(ctx context.Context, result *BootstrapStepResult) (bool, error) {
+	if err := c.validateSharedResourcesNamespaces(); err != nil {
+		result.addError(err)
+		return false, err
+	}
+
+	createdAny := false
+	errs := []error{}
+	for _, ns := range c.sharedResourcesNamespaces() {
+		created, err := c.ensureNamespaceExists(ctx, ns)
 		if err != nil {
-			glog.Errorf("Unable to create default security context constraint %s.  Got error: %v", scc.Name, err)
+			errs = append(errs, err)
+			result.addError(err)
 			continue
 		}
-		glog.Infof("Created default security context constraint %s", scc.Name)
+		createdAny = createdAny || created
+		result.inc("reconciled")
 	}
+	return createdAny, utilerrors.NewAggregate(errs)
 }
 
-// ensureComponentAuthorizationRules initializes the cluster policies
-func (c *MasterConfig) ensureComponentAuthorizationRules() {
-	clusterPolicyStorage, err := clusterpolicystorage.NewREST(c.RESTOptionsGetter)
-	if err != nil {
-		glog.Errorf("Error creating policy storage: %v", err)
+// ensureOpenShiftSharedResourcesNamespace is called as part of global policy initialization to ensure the
+// shared resources namespaces exist
+func (c *MasterConfig) ensureOpenShiftSharedResourcesNamespace(ctx context.Context) {
+	if err := c.validateSharedResourcesNamespaces(); err != nil {
+		c.bootstrapLog().Error(err, "Error validating shared resources namespaces")
 		return
 	}
-	clusterPolicyRegistry := clusterpolicyregistry.NewRegistry(clusterPolicyStorage)
-	ctx := apirequest.WithNamespace(apirequest.NewContext(), "")
+	for _, ns := range c.sharedResourcesNamespaces() {
+		c.ensureNamespaceExists(ctx, ns)
+	}
+}
 
-	if _, err := clusterPolicyRegistry.GetClusterPolicy(ctx, authorizationapi.PolicyName, &metav1.GetOptions{}); kapierror.IsNotFound(err) {
-		glog.Infof("No cluster policy found.  Creating bootstrap policy based on: %v", c.Options.PolicyConfig.BootstrapPolicyFile)
+// ensureOpenShiftInfraNamespace is called as part of global policy initialization to ensure infra namespace
+// exists. If the namespace is found Terminating (see waitForInfraNamespaceActive), it waits for that to clear
+// before reconciling any roles into it. Every controller role and role binding reconcile is attempted even if
+// earlier ones failed - a single bad role must not prevent the rest from being fixed up - but any failures are
+// collected into the returned aggregate error rather than only being logged, so a caller can no longer mistake
+// a partially reconciled set of controller permissions for success. The returned bool reports whether the
+// infra namespace was newly created by this call, so a caller can gate one-time post-create initialization on
+// it rather than re-running it against a namespace that already existed.
+func (c *MasterConfig) ensureOpenShiftInfraNamespace(ctx context.Context, result *BootstrapStepResult) (bool, error) {
+	ns := c.infraNamespace()
+	labels := c.bootstrapNamespaceLabels(c.infraNamespaceLabels())
 
-		if err := admin.OverwriteBootstrapPolicy(c.RESTOptionsGetter, c.Options.PolicyConfig.BootstrapPolicyFile, admin.CreateBootstrapPolicyFileFullCommand, true, ioutil.Discard); err != nil {
-			glog.Errorf("Error creating bootstrap policy: %v", err)
-		}
+	// Ensure namespace exists
+	toCreate := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+	stampBootstrapNamespaceMetadata(toCreate, labels)
+	c.applyNamespaceMutator(toCreate)
 
-		// these are namespaced, so we can't reconcile them.  Just try to put them in until we work against rbac
-		// This only had to hold us until the transition is complete
-		// TODO remove this block and use a post-starthook
-		// ensure bootstrap namespaced roles are created or reconciled
-		for namespace, roles := range kbootstrappolicy.NamespaceRoles() {
-			for _, rbacRole := range roles {
-				role := &authorizationapi.Role{}
-				if err := authorizationapi.Convert_rbac_Role_To_authorization_Role(&rbacRole, role, nil); err != nil {
-					utilruntime.HandleError(fmt.Errorf("unable to convert role.%s/%s in %v: %v", rbac.GroupName, rbacRole.Name, namespace, err))
-					continue
-				}
-				if _, err := c.PrivilegedLoopbackOpenShiftClient.Roles(namespace).Create(role); err != nil {
-					// don't fail on failures, try to create as many as you can
-					utilruntime.HandleError(fmt.Errorf("unable to reconcile role.%s/%s in %v: %v", rbac.GroupName, role.Name, namespace, err))
-				}
-			}
+	var namespace *kapi.Namespace
+	created := false
+	err := c.retryOnTransientError(ctx, func() error {
+		var createErr error
+		namespace, createErr = c.bootstrapKubeClient().Core().Namespaces().Create(toCreate)
+		return createErr
+	})
+	if kapierror.IsAlreadyExists(err) {
+		// Get the persisted namespace and patch the bootstrap labels onto it, since it predates this bootstrap run
+		namespace, err = c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+		if err != nil {
+			c.bootstrapLog().Error(err, "Error getting namespace", "namespace", ns)
+			result.addError(err)
+			return false, err
 		}
-
-		// ensure bootstrap namespaced rolebindings are created or reconciled
-		for namespace, roleBindings := range kbootstrappolicy.NamespaceRoleBindings() {
-			for _, rbacRoleBinding := range roleBindings {
-				roleBinding := &authorizationapi.RoleBinding{}
-				if err := authorizationapi.Convert_rbac_RoleBinding_To_authorization_RoleBinding(&rbacRoleBinding, roleBinding, nil); err != nil {
-					utilruntime.HandleError(fmt.Errorf("unable to convert rolebinding.%s/%s in %v: %v", rbac.GroupName, rbacRoleBinding.Name, namespace, err))
-					continue
-				}
-				if _, err := c.PrivilegedLoopbackOpenShiftClient.RoleBindings(namespace).Create(roleBinding); err != nil {
-					// don't fail on failures, try to create as many as you can
-					utilruntime.HandleError(fmt.Errorf("unable to reconcile rolebinding.%s/%s in %v: %v", rbac.GroupName, roleBinding.Name, namespace, err))
-				}
-			}
+		if err := c.patchBootstrapNamespaceMetadata(namespace, labels); err != nil {
+			result.addError(err)
 		}
-
+		result.inc("skipped")
+	} else if err != nil {
+		c.bootstrapLog().Error(err, "Error creating namespace", "namespace", ns)
+		result.addError(err)
+		return false, err
 	} else {
-		glog.V(2).Infof("Ignoring bootstrap policy file because cluster policy found")
+		created = true
+		result.inc("created")
+		c.auditBootstrapMutation("namespace-create", ns)
 	}
 
-	// Reconcile roles that must exist for the cluster to function
-	// Be very judicious about what is placed in this list, since it will be enforced on every server start
+	c.bootstrapLog().Info("Observed infra namespace phase", "namespace", ns, "phase", namespace.Status.Phase)
+	namespace, err = c.waitForInfraNamespaceActive(ns, namespace)
+	if err != nil {
+		c.bootstrapLog().Error(err, "Infra namespace did not clear the Terminating phase in time", "namespace", ns)
+		result.addError(err)
+		return false, err
+	}
+
+	errs := []error{}
+
+	// Reconcile every controller role in a single call rather than one RunReconcileClusterRoles invocation
+	// per role: ReconcileClusterRolesOptions already accepts a list, so issuing dozens of one-role calls was
+	// strictly wasteful.
+	controllerRoles := bootstrappolicy.ControllerRoles()
+	controllerRoleNames, duplicateControllerRoleNames := dedupeControllerRoleNames(controllerRoles)
+	if len(duplicateControllerRoleNames) > 0 {
+		c.bootstrapLog().Info("Duplicate controller role names in bootstrappolicy.ControllerRoles; reconciling each once", "duplicates", duplicateControllerRoleNames)
+	}
 	reconcileRoles := &policy.ReconcileClusterRolesOptions{
-		RolesToReconcile: []string{bootstrappolicy.DiscoveryRoleName},
-		Confirmed:        true,
+		RolesToReconcile: controllerRoleNames,
+		Confirmed:        !c.DryRunBootstrap,
 		Union:            true,
-		Out:              ioutil.Discard,
+		Out:              c.bootstrapOutput(),
 		RoleClient:       c.PrivilegedLoopbackOpenShiftClient.ClusterRoles(),
 	}
-	if err := reconcileRoles.RunReconcileClusterRoles(nil, nil); err != nil {
-		glog.Errorf("Could not auto reconcile roles: %v\n", err)
+	rolesReconciled := 0
+	if reconcileErr := reconcileRoles.RunReconcileClusterRoles(nil, nil); reconcileErr != nil {
+		if isAuthDeniedReconcileError(reconcileErr) {
+			err := c.classifyLoopbackAuthFailure(fmt.Errorf("the master's loopback identity lacks permission to reconcile one or more controller roles (escalation protection): %v", reconcileErr))
+			c.bootstrapLog().Error(err, "Controller role reconcile forbidden by escalation protection; check the loopback identity's permissions", "namespace", ns)
+			result.addError(err)
+			result.inc("controllerRolesReconcileForbidden")
+			errs = append(errs, err)
+		} else {
+			err := fmt.Errorf("could not reconcile controller roles: %v", reconcileErr)
+			c.bootstrapLog().Error(err, "Could not auto reconcile controller roles", "namespace", ns)
+			result.addError(err)
+			errs = append(errs, err)
+		}
+	} else {
+		rolesReconciled = len(controllerRoleNames)
 	}
+	result.Counts["controllerRolesReconciled"] += rolesReconciled
 
-	// Reconcile rolebindings that must exist for the cluster to function
-	// Be very judicious about what is placed in this list, since it will be enforced on every server start
+	controllerRoleBindings := bootstrappolicy.ControllerRoleBindings()
+	controllerRoleBindingNames := make([]string, 0, len(controllerRoleBindings))
+	for _, roleBinding := range controllerRoleBindings {
+		controllerRoleBindingNames = append(controllerRoleBindingNames, roleBinding.RoleRef.Name)
+	}
 	reconcileRoleBindings := &policy.ReconcileClusterRoleBindingsOptions{
-		RolesToReconcile:  []string{bootstrappolicy.DiscoveryRoleName},
-		Confirmed:         true,
+		RolesToReconcile:  controllerRoleBindingNames,
+		Confirmed:         !c.DryRunBootstrap,
 		Union:             true,
-		Out:               ioutil.Discard,
+		Out:               c.bootstrapOutput(),
 		RoleBindingClient: c.PrivilegedLoopbackOpenShiftClient.ClusterRoleBindings(),
 	}
-	if err := reconcileRoleBindings.RunReconcileClusterRoleBindings(nil, nil); err != nil {
-		glog.Errorf("Could not auto reconcile role bindings: %v\n", err)
+	roleBindingsReconciled := 0
+	if reconcileErr := reconcileRoleBindings.RunReconcileClusterRoleBindings(nil, nil); reconcileErr != nil {
+		if isAuthDeniedReconcileError(reconcileErr) {
+			err := c.classifyLoopbackAuthFailure(fmt.Errorf("the master's loopback identity lacks permission to reconcile one or more controller role bindings (escalation protection): %v", reconcileErr))
+			c.bootstrapLog().Error(err, "Controller role binding reconcile forbidden by escalation protection; check the loopback identity's permissions", "namespace", ns)
+			result.addError(err)
+			result.inc("controllerRoleBindingsReconcileForbidden")
+			errs = append(errs, err)
+		} else {
+			err := fmt.Errorf("could not reconcile controller role bindings: %v", reconcileErr)
+			c.bootstrapLog().Error(err, "Could not auto reconcile controller role bindings", "namespace", ns)
+			result.addError(err)
+			errs = append(errs, err)
+		}
+	} else {
+		roleBindingsReconciled = len(controllerRoleBindingNames)
+	}
+	result.Counts["controllerRoleBindingsReconciled"] += roleBindingsReconciled
+
+	if err := c.ensureAdditionalControllerRoleBindings(controllerRoleNames, result); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		c.auditBootstrapMutation("role-reconcile", ns)
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	if err := c.ensureInfraNamespaceQuota(ns, result); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.ensureInfraNamespaceLimitRange(ns, result); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.applyNamespaceCreationObjects(ns); err != nil {
+		result.addError(err)
+		errs = append(errs, err)
+	}
+
+	return created, utilerrors.NewAggregate(errs)
+}
+
+// dedupeControllerRoleNames returns the unique names from roles, preserving first-occurrence order, along
+// with any names that appeared more than once. Two bootstrappolicy.ControllerRoles() entries sharing a name
+// is a packaging mistake, but reconciling the same role name twice is merely wasteful rather than harmful, so
+// this collapses the duplicate down to a single reconcile rather than failing bootstrap over it; the returned
+// duplicates are for the caller to log so the underlying mistake still gets noticed and fixed.
+func dedupeControllerRoleNames(roles []rbac.ClusterRole) (names []string, duplicates []string) {
+	seen := sets.NewString()
+	for _, role := range roles {
+		if seen.Has(role.Name) {
+			duplicates = append(duplicates, role.Name)
+			continue
+		}
+		seen.Insert(role.Name)
+		names = append(names, role.Name)
+	}
+	return names, duplicates
+}
+
+// ensureAdditionalControllerRoleBindings reconciles PolicyConfig.AdditionalControllerRoleBindings alongside
+// the built-in controller role bindings, using the same union-subjects behavior as
+// reconcileNamespacedRoleBinding: an admin-added subject on an existing binding is preserved rather than
+// overwritten. knownControllerRoles is the set of built-in controller role names; an entry naming a role
+// outside that set and outside AlwaysReconcileClusterRoles is logged and skipped, since bootstrap has no
+// guarantee such a role exists.
+func (c *MasterConfig) ensureAdditionalControllerRoleBindings(knownControllerRoles []string, result *BootstrapStepResult) error {
+	additional := c.Options.PolicyConfig.AdditionalControllerRoleBindings
+	if len(additional) == 0 {
+		return nil
+	}
+
+	knownRoles := sets.NewString(knownControllerRoles...)
+	knownRoles.Insert(c.Options.PolicyConfig.AlwaysReconcileClusterRoles...)
+
+	client := c.PrivilegedLoopbackOpenShiftClient.ClusterRoleBindings()
+	errs := []error{}
+	for _, binding := range additional {
+		if !knownRoles.Has(binding.RoleName) {
+			err := fmt.Errorf("additional controller role binding %q references unknown role %q", binding.Name, binding.RoleName)
+			c.bootstrapLog().Error(err, "Skipping additional controller role binding with unknown role")
+			result.addError(err)
+			continue
+		}
+		roleRef := kapi.ObjectReference{Name: binding.RoleName}
+		if err := reconcileClusterRoleBindingSubjects(client, binding.Name, roleRef, binding.Subjects); err != nil {
+			err := fmt.Errorf("could not reconcile additional controller role binding %q: %v", binding.Name, err)
+			c.bootstrapLog().Error(err, "Could not reconcile additional controller role binding")
+			result.addError(err)
+			errs = append(errs, err)
+			continue
+		}
+		result.inc("additionalControllerRoleBindingsReconciled")
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// reconcileClusterRoleBindingSubjects is the cluster-scoped analogue of reconcileNamespacedRoleBinding's
+// subject-union behavior. Unlike a namespaced RoleBinding an admin might hand-edit, roleRef here is always
+// supplied by the caller and never recomputed from an existing object, so there's no RoleRef-drift case to
+// handle.
+func reconcileClusterRoleBindingSubjects(client osclient.ClusterRoleBindingInterface, name string, roleRef kapi.ObjectReference, subjects []kapi.ObjectReference) error {
+	existing, err := client.Get(name, metav1.GetOptions{})
+	if kapierror.IsNotFound(err) {
+		_, err := client.Create(&authorizationapi.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    roleRef,
+			Subjects:   subjects,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	merged := unionSubjects(existing.Subjects, subjects)
+	if reflect.DeepEqual(existing.Subjects, merged) {
+		return nil
+	}
+	existing.Subjects = merged
+	_, err = client.Update(existing)
+	return err
+}
+
+// infraNamespaceQuotaName and infraNamespaceLimitRangeName are the names given to the ResourceQuota and
+// LimitRange bootstrap optionally creates in the OpenShift infra namespace.
+const (
+	infraNamespaceQuotaName      = "infra"
+	infraNamespaceLimitRangeName = "infra"
+)
+
+// ensureInfraNamespaceQuota creates the ResourceQuota described by PolicyConfig.InfraNamespaceQuota in the
+// infra namespace, if configured. It's a no-op when InfraNamespaceQuota is unset, and idempotent: an
+// AlreadyExists error from a previous bootstrap run is treated as success rather than reconciled, since
+// operators are expected to edit an existing quota directly rather than have bootstrap overwrite their changes.
+func (c *MasterConfig) ensureInfraNamespaceQuota(ns string, result *BootstrapStepResult) error {
+	if c.Options.PolicyConfig.InfraNamespaceQuota == nil {
+		return nil
+	}
+
+	quota := &kapi.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: infraNamespaceQuotaName, Namespace: ns},
+		Spec:       *c.Options.PolicyConfig.InfraNamespaceQuota,
+	}
+	_, err := c.bootstrapKubeClient().Core().ResourceQuotas(ns).Create(quota)
+	if kapierror.IsAlreadyExists(err) {
+		result.inc("skipped")
+		return nil
+	}
+	if err != nil {
+		c.bootstrapLog().Error(err, "Unable to create infra namespace resource quota", "namespace", ns)
+		result.addError(err)
+		return err
+	}
+	c.bootstrapLog().Info("Created infra namespace resource quota", "namespace", ns)
+	result.inc("created")
+	return nil
+}
+
+// ensureInfraNamespaceLimitRange creates the LimitRange described by PolicyConfig.InfraNamespaceLimitRange
+// in the infra namespace, if configured. It's a no-op when InfraNamespaceLimitRange is unset, and
+// idempotent in the same way as ensureInfraNamespaceQuota.
+func (c *MasterConfig) ensureInfraNamespaceLimitRange(ns string, result *BootstrapStepResult) error {
+	if c.Options.PolicyConfig.InfraNamespaceLimitRange == nil {
+		return nil
+	}
+
+	limitRange := &kapi.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: infraNamespaceLimitRangeName, Namespace: ns},
+		Spec:       *c.Options.PolicyConfig.InfraNamespaceLimitRange,
+	}
+	_, err := c.bootstrapKubeClient().Core().LimitRanges(ns).Create(limitRange)
+	if kapierror.IsAlreadyExists(err) {
+		result.inc("skipped")
+		return nil
+	}
+	if err != nil {
+		c.bootstrapLog().Error(err, "Unable to create infra namespace limit range", "namespace", ns)
+		result.addError(err)
+		return err
+	}
+	c.bootstrapLog().Info("Created infra namespace limit range", "namespace", ns)
+	result.inc("created")
+	return nil
+}
+
+// isAuthDeniedReconcileError reports whether err - or, if err is an aggregate, any error it wraps - is a
+// Forbidden or Unauthorized error. RunReconcileClusterRoles and RunReconcileClusterRoleBindings return
+// Forbidden when the reconciling identity (normally the master's privileged loopback client) isn't allowed to
+// grant a role's rules, most commonly because of the API server's escalation protection; Unauthorized shows up
+// the same way when the identity's credentials themselves are rejected outright. Either is distinguished here
+// from a transient error like a connection failure, so callers can log an actionable "the loopback identity is
+// missing permissions" warning - or, once enough of these accumulate, diagnose the loopback client itself as
+// misconfigured (see MasterConfig.classifyLoopbackAuthFailure) - instead of a generic reconcile failure.
+func isAuthDeniedReconcileError(err error) bool {
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		for _, aggErr := range agg.Errors() {
+			if kapierror.IsForbidden(aggErr) || kapierror.IsUnauthorized(aggErr) {
+				return true
+			}
+		}
+		return false
+	}
+	return kapierror.IsForbidden(err) || kapierror.IsUnauthorized(err)
+}
+
+// ErrLoopbackUnauthorized is returned once MasterConfig.classifyLoopbackAuthFailure has seen at least
+// defaultLoopbackUnauthorizedThreshold Forbidden/Unauthorized reconcile failures (see
+// isAuthDeniedReconcileError) against the privileged loopback client over the life of the MasterConfig (the
+// count is not reset between EnsureBootstrapPolicy runs). That pattern almost always means the loopback client's credentials or RBAC bindings are
+// misconfigured, rather than that any one role or role binding is legitimately disallowed by escalation
+// protection, so EnsureBootstrapPolicy short-circuits on it instead of limping through the remaining steps,
+// each of which would fail the same way and only add noise.
+type ErrLoopbackUnauthorized struct {
+	// Err is the last Forbidden/Unauthorized reconcile error that triggered this diagnosis.
+	Err error
+}
+
+func (e *ErrLoopbackUnauthorized) Error() string {
+	return fmt.Sprintf("the master's privileged loopback client was repeatedly rejected as forbidden/unauthorized; check its credentials and RBAC bindings: %v", e.Err)
+}
+
+// Cause returns the underlying reconcile error, for callers that unwrap with errors.Cause.
+func (e *ErrLoopbackUnauthorized) Cause() error {
+	return e.Err
+}
+
+// defaultLoopbackUnauthorizedThreshold is how many Forbidden/Unauthorized reconcile failures against the
+// privileged loopback client, counted across every bootstrap step of every EnsureBootstrapPolicy run on the
+// same MasterConfig, it takes
+// before classifyLoopbackAuthFailure concludes the loopback client itself is misconfigured - as opposed to a
+// single role legitimately tripping escalation protection - and starts returning ErrLoopbackUnauthorized.
+const defaultLoopbackUnauthorizedThreshold = 2
+
+// classifyLoopbackAuthFailure records err - already confirmed Forbidden or Unauthorized by the caller via
+// isAuthDeniedReconcileError - against this MasterConfig's running count of such failures, and once that
+// count reaches defaultLoopbackUnauthorizedThreshold, wraps err as ErrLoopbackUnauthorized. Below the
+// threshold, err is returned unchanged. The count is cumulative for the life of this MasterConfig rather than
+// reset per EnsureBootstrapPolicy call, since a loopback client that was misconfigured on the last run is
+// still misconfigured on this one.
+func (c *MasterConfig) classifyLoopbackAuthFailure(err error) error {
+	if atomic.AddInt32(&c.loopbackUnauthorizedCount, 1) >= defaultLoopbackUnauthorizedThreshold {
+		return &ErrLoopbackUnauthorized{Err: err}
+	}
+	return err
+}
+
+// parallelize calls fn(i) for each i in [0, pieces), using up to workers goroutines at once and claiming
+// batchSize indices per turn so a worker finishes a whole batch - and its log output stays contiguous -
+// before yielding for more work. It returns every non-nil error fn produced, in a deterministic order
+// matching i, regardless of which goroutine finishes first, so callers can log failures in a stable,
+// reproducible order.
+func parallelize(pieces, workers, batchSize int, fn func(i int) error) []error {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > pieces {
+		workers = pieces
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	type batch struct{ start, end int }
+	batchCount := (pieces + batchSize - 1) / batchSize
+	errs := make([]error, pieces)
+	work := make(chan batch, batchCount)
+	for start := 0; start < pieces; start += batchSize {
+		end := start + batchSize
+		if end > pieces {
+			end = pieces
+		}
+		work <- batch{start, end}
+	}
+	close(work)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer utilruntime.HandleCrash()
+			for b := range work {
+				for i := b.start; i < b.end; i++ {
+					errs[i] = fn(i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := []error{}
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// defaultNamespaceInitConcurrency is how many namespaces are initialized at once during a mass service account
+// role binding initialization sweep when PolicyConfig.Bootstrap.NamespaceInitConcurrency is left at its zero
+// value.
+const defaultNamespaceInitConcurrency = 10
+
+// namespaceInitConcurrency returns the configured worker pool size for a mass namespace service account role
+// binding initialization sweep, falling back to defaultNamespaceInitConcurrency when unset.
+func (c *MasterConfig) namespaceInitConcurrency() int {
+	if c.Options.PolicyConfig.Bootstrap.NamespaceInitConcurrency > 0 {
+		return c.Options.PolicyConfig.Bootstrap.NamespaceInitConcurrency
+	}
+	return defaultNamespaceInitConcurrency
+}
+
+// ReconcileNamespaceServiceAccountRoleBindings initializes service account role bindings across every
+// namespace in namespaces, using a bounded worker pool sized by PolicyConfig.Bootstrap.NamespaceInitConcurrency
+// so a mass initialization sweep - for example, over every project during an upgrade - can't overwhelm the
+// apiserver with unbounded concurrent requests. Every namespace is attempted even if earlier ones failed, since
+// ensureNamespaceServiceAccountRoleBindings already logs its own failures rather than returning them.
+func (c *MasterConfig) ReconcileNamespaceServiceAccountRoleBindings(namespaces []*kapi.Namespace) {
+	parallelize(len(namespaces), c.namespaceInitConcurrency(), 1, func(i int) error {
+		c.ensureNamespaceServiceAccountRoleBindings(namespaces[i])
+		return nil
+	})
+}
+
+// ensureDefaultNamespaceServiceAccountRoles initializes roles for service accounts in the default namespace.
+// If Options.PolicyConfig.Bootstrap.SkipDefaultNamespaceInitialization is set, it skips waiting for the
+// default namespace entirely and logs at info level instead, for deployments that intentionally omit it.
+func (c *MasterConfig) ensureDefaultNamespaceServiceAccountRoles(ctx context.Context, result *BootstrapStepResult) {
+	if c.Options.PolicyConfig.Bootstrap.SkipDefaultNamespaceInitialization {
+		c.bootstrapLog().Info("Skipping default namespace service account role initialization", "namespace", metav1.NamespaceDefault)
+		return
+	}
+
+	namespace := c.waitForDefaultNamespace(ctx)
+	if namespace == nil {
+		result.addError(fmt.Errorf("timed out waiting for the %q namespace to exist", metav1.NamespaceDefault))
+		return
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+	result.inc("reconciled")
+}
+
+// waitForDefaultNamespace blocks until the default namespace exists (or DefaultNamespaceWaitTimeout elapses,
+// or ctx is cancelled), logging and returning nil on timeout, cancellation, or a non-NotFound API error. When
+// WatchDefaultNamespace is set it watches for the namespace instead of polling, falling back to polling if the
+// watch can't be established. The polling path retries with exponential backoff, starting at
+// DefaultNamespaceWaitInterval and capped at DefaultNamespaceWaitMaxInterval, and logs the number of attempts
+// made and total time waited on both success and failure so a slow-starting cluster is easy to diagnose.
+func (c *MasterConfig) waitForDefaultNamespace(ctx context.Context) *kapi.Namespace {
+	timeout := c.DefaultNamespaceWaitTimeout
+	if timeout == 0 {
+		timeout = DefaultNamespaceWaitTimeoutDefault
+	}
+
+	if c.WatchDefaultNamespace {
+		namespace, err := c.watchForDefaultNamespace(ctx, timeout)
+		if err == nil {
+			return namespace
+		}
+		glog.V(2).Infof("Falling back to polling for the %q namespace after watch failed: %v", metav1.NamespaceDefault, err)
+	}
+
+	start := time.Now()
+	attempts := 0
+	interval := DefaultNamespaceWaitInterval
+	stop := ctxOrTimeoutDone(ctx, timeout)
+
+	var namespace *kapi.Namespace
+	var getErr error
+	for namespace == nil && getErr == nil {
+		attempts++
+		ns, err := c.bootstrapKubeClient().Core().Namespaces().Get(metav1.NamespaceDefault, metav1.GetOptions{})
+		if err == nil {
+			namespace = ns
+			break
+		}
+		if !kapierror.IsNotFound(err) {
+			// a non-NotFound error is not going to be fixed by waiting longer, so bail out immediately
+			getErr = err
+			break
+		}
+
+		select {
+		case <-stop:
+			getErr = err
+		case <-time.After(interval):
+			interval = nextDefaultNamespaceWaitInterval(interval)
+		}
+	}
+
+	if namespace != nil {
+		c.bootstrapLog().Info("Default namespace found", "namespace", metav1.NamespaceDefault, "attempts", attempts, "waited", time.Since(start))
+		return namespace
+	}
+	if getErr != nil && !kapierror.IsNotFound(getErr) {
+		c.bootstrapLog().Error(getErr, "Error adding service account roles to namespace", "namespace", metav1.NamespaceDefault, "attempts", attempts, "waited", time.Since(start))
+		return nil
+	}
+	c.bootstrapLog().Info("Namespace not found, could not initialize namespace", "namespace", metav1.NamespaceDefault, "attempts", attempts, "waited", time.Since(start), "lastError", getErr)
+	return nil
+}
+
+// nextDefaultNamespaceWaitInterval doubles interval, capping the result at DefaultNamespaceWaitMaxInterval.
+func nextDefaultNamespaceWaitInterval(interval time.Duration) time.Duration {
+	next := interval * 2
+	if next > DefaultNamespaceWaitMaxInterval {
+		return DefaultNamespaceWaitMaxInterval
+	}
+	return next
+}
+
+// ctxOrTimeoutDone returns a channel that closes when ctx is done or timeout elapses, whichever comes first.
+func ctxOrTimeoutDone(ctx context.Context, timeout time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	timer := time.NewTimer(timeout)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+		close(done)
+	}()
+	return done
+}
+
+// watchForDefaultNamespace establishes a Watch on the default namespace and blocks until an ADDED or MODIFIED
+// event arrives, timeout elapses, or ctx is cancelled. It exists so master startup doesn't have to poll the
+// apiserver once a second while it's already under load from the rest of bootstrap.
+func (c *MasterConfig) watchForDefaultNamespace(ctx context.Context, timeout time.Duration) (*kapi.Namespace, error) {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", metav1.NamespaceDefault)
+	w, err := c.bootstrapKubeClient().Core().Namespaces().Watch(metav1.ListOptions{FieldSelector: fieldSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+	defer w.Stop()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil, fmt.Errorf("watch on %q namespace closed before it appeared", metav1.NamespaceDefault)
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				ns, ok := event.Object.(*kapi.Namespace)
+				if !ok {
+					continue
+				}
+				return ns, nil
+			}
+		case <-timer.C:
+			return nil, fmt.Errorf("timed out after %v waiting for the %q namespace", timeout, metav1.NamespaceDefault)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// defaultServiceAccountRolesInitializedAnnotation is the annotation key used to record that a namespace's
+// bootstrap service account role bindings have been applied, unless overridden by
+// PolicyConfig.ServiceAccountRolesInitializedAnnotation.
+const defaultServiceAccountRolesInitializedAnnotation = "openshift.io/sa.initialized-roles"
+
+// serviceAccountRolesInitializedAnnotation returns the configured annotation key, falling back to
+// defaultServiceAccountRolesInitializedAnnotation when unset.
+func (c *MasterConfig) serviceAccountRolesInitializedAnnotation() string {
+	if key := c.Options.PolicyConfig.ServiceAccountRolesInitializedAnnotation; len(key) > 0 {
+		return key
+	}
+	return defaultServiceAccountRolesInitializedAnnotation
+}
+
+// missingServiceAccountRoleBindings returns the RoleRef names, in desired's order, of every desired bootstrap
+// role binding whose subjects aren't all already present in an existing role binding for the same role.
+// serviceAccountRoleBindingsUpToDate and VerifyAllProjectServiceAccountRoleBindings both build on this so a
+// namespace's fast "is it fully initialized" check can't drift from a health report's finer-grained "what's
+// actually missing" answer.
+func missingServiceAccountRoleBindings(accessor policy.RoleBindingAccessor, desired []authorizationapi.RoleBinding) ([]string, error) {
+	var missing []string
+	for _, binding := range desired {
+		existingBindings, err := accessor.GetExistingRoleBindingsForRole(binding.RoleRef.Namespace, binding.RoleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		bindingComplete := true
+	subjectCheck:
+		for _, subject := range binding.Subjects {
+			for _, existingBinding := range existingBindings {
+				for _, existingSubject := range existingBinding.Subjects {
+					if existingSubject.Kind == subject.Kind && existingSubject.Name == subject.Name && existingSubject.Namespace == subject.Namespace {
+						continue subjectCheck
+					}
+				}
+			}
+			bindingComplete = false
+			break subjectCheck
+		}
+		if !bindingComplete {
+			missing = append(missing, binding.RoleRef.Name)
+		}
+	}
+	return missing, nil
+}
+
+// serviceAccountRoleBindingsUpToDate checks, for each desired bootstrap role binding, that every one of its
+// subjects appears in an existing role binding for the same role. It's used to detect drift between the
+// sa.initialized-roles annotation and the actual state of a namespace's role bindings.
+func serviceAccountRoleBindingsUpToDate(accessor policy.RoleBindingAccessor, desired []authorizationapi.RoleBinding) (bool, error) {
+	missing, err := missingServiceAccountRoleBindings(accessor, desired)
+	if err != nil {
+		return false, err
+	}
+	return len(missing) == 0, nil
+}
+
+// NamespaceVerification is a single namespace's result from VerifyAllProjectServiceAccountRoleBindings: which,
+// if any, of its bootstrap service account project role bindings are missing.
+type NamespaceVerification struct {
+	Namespace string
+	// MissingRoleBindings names the bootstrap roles (for example "system:image-puller") this namespace's
+	// bootstrap service accounts aren't bound to yet. Never empty - a namespace with no missing bindings isn't
+	// included in VerifyAllProjectServiceAccountRoleBindings' result at all.
+	MissingRoleBindings []string
+}
+
+// VerifyAllProjectServiceAccountRoleBindings sweeps every namespace and reports which ones are missing any of
+// their bootstrap service account project role bindings, checking the actual role bindings via
+// missingServiceAccountRoleBindings rather than trusting the sa.initialized-roles annotation
+// ensureNamespaceServiceAccountRoleBindings relies on. It's purely read-only - only
+// EnsureServiceAccountRoleBindings and ensureNamespaceServiceAccountRoleBindings ever create or update a role
+// binding - so it's safe to run at any time, for example from a health check or diagnostics report. A namespace
+// missing nothing is left out of the result entirely, so an empty, nil-error return means every namespace is
+// fully initialized. A single namespace's comparison failing doesn't prevent the others from being checked; its
+// error is aggregated into the returned error alongside whatever results were gathered from the rest.
+func (c *MasterConfig) VerifyAllProjectServiceAccountRoleBindings() ([]NamespaceVerification, error) {
+	namespaces, err := c.KubeClientsetInternal().Core().Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []NamespaceVerification
+	var errs []error
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		desired := bootstrappolicy.GetBootstrapServiceAccountProjectRoleBindings(namespace.Name)
+		accessor := policy.NewLocalRoleBindingAccessor(namespace.Name, c.PrivilegedLoopbackOpenShiftClient)
+		missing, err := missingServiceAccountRoleBindings(accessor, desired)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("namespace %s: %v", namespace.Name, err))
+			continue
+		}
+		if len(missing) > 0 {
+			results = append(results, NamespaceVerification{Namespace: namespace.Name, MissingRoleBindings: missing})
+		}
+	}
+	return results, utilerrors.NewAggregate(errs)
+}
+
+// ListUninitializedServiceAccountRoleNamespaces lists the names of every namespace lacking the
+// sa.initialized-roles annotation (see serviceAccountRolesInitializedAnnotation), for tracking mass
+// initialization progress - for example during an upgrade that stamps the annotation namespace by namespace.
+// Like VerifyAllProjectServiceAccountRoleBindings it's purely read-only, but unlike it, it trusts the annotation
+// rather than checking actual role bindings, so it's cheap enough to poll repeatedly from a progress dashboard.
+func (c *MasterConfig) ListUninitializedServiceAccountRoleNamespaces() ([]string, error) {
+	namespaces, err := c.KubeClientsetInternal().Core().Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := c.serviceAccountRolesInitializedAnnotation()
+	var uninitialized []string
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		if namespace.Annotations[annotation] != "true" {
+			uninitialized = append(uninitialized, namespace.Name)
+		}
+	}
+	return uninitialized, nil
+}
+
+// EnsureServiceAccountRoleBindingsOptions configures EnsureServiceAccountRoleBindings. The zero value runs with
+// the same defaults EnsureBootstrapPolicy uses: the default initialized-roles annotation, no forced
+// reinitialization or verification of an already-initialized namespace, and quick retries with a small jitter
+// (defaultServiceAccountRoleRetryBackoff).
+type EnsureServiceAccountRoleBindingsOptions struct {
+	// Namespace, when set, is used directly for the initialized-roles annotation check instead of being looked
+	// up via kubeClient, saving a redundant Get when the caller already has a fresh namespace object. Left nil,
+	// EnsureServiceAccountRoleBindings fetches the namespace via kubeClient itself.
+	Namespace *kapi.Namespace
+	// InitializedAnnotation overrides the annotation key used to record that the namespace's bootstrap service
+	// account role bindings have been applied. Defaults to defaultServiceAccountRolesInitializedAnnotation when
+	// empty.
+	InitializedAnnotation string
+	// ForceReinitialize, when true, re-applies bindings even if the namespace is already marked initialized.
+	ForceReinitialize bool
+	// VerifyExisting, when true and the namespace is already marked initialized, checks the actual role
+	// bindings against the desired set and re-applies any that are missing.
+	VerifyExisting bool
+	// RetryBackoff controls the backoff used when retrying role-binding and annotation conflicts. The zero
+	// value uses defaultServiceAccountRoleRetryBackoff.
+	RetryBackoff utilwait.Backoff
+	// DesiredRoleBindings, when non-empty, replaces bootstrappolicy.GetBootstrapServiceAccountProjectRoleBindings
+	// as what this namespace's service account role bindings must contain. See
+	// MasterConfig.sharedResourcesNamespaceRoleBindings for the caller that uses this to give the shared
+	// resources namespace different bindings than an ordinary project.
+	DesiredRoleBindings []authorizationapi.RoleBinding
+	// Log receives progress and error messages. Defaults to glogBootstrapLogger{} when nil.
+	Log BootstrapLogger
+}
+
+// EnsureServiceAccountRoleBindings ensures the bootstrap service account project role bindings exist in the
+// namespace named namespaceName, using roleBindingClient to read and add role bindings and kubeClient to read
+// and stamp the namespace's initialized-roles annotation. It runs the same
+// GetBootstrapServiceAccountProjectRoleBindings + AddRole + annotation flow EnsureBootstrapPolicy applies to
+// every bootstrap namespace, extracted here so callers outside the bootstrap path - for example an admission
+// plugin reacting to a freshly created project - can re-run it independently of a MasterConfig.
+func EnsureServiceAccountRoleBindings(roleBindingClient osclient.Interface, kubeClient kclientsetinternal.Interface, namespaceName string, opts EnsureServiceAccountRoleBindingsOptions) error {
+	log := opts.Log
+	if log == nil {
+		log = glogBootstrapLogger{}
+	}
+	initializedAnnotation := opts.InitializedAnnotation
+	if len(initializedAnnotation) == 0 {
+		initializedAnnotation = defaultServiceAccountRolesInitializedAnnotation
+	}
+	backoff := opts.RetryBackoff
+	if backoff.Duration == 0 && backoff.Steps == 0 {
+		backoff = defaultServiceAccountRoleRetryBackoff
+	}
+
+	namespace := opts.Namespace
+	if namespace == nil {
+		var err error
+		namespace, err = kubeClient.Core().Namespaces().Get(namespaceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	}
+	desiredBindings := opts.DesiredRoleBindings
+	if len(desiredBindings) == 0 {
+		desiredBindings = bootstrappolicy.GetBootstrapServiceAccountProjectRoleBindings(namespaceName)
+	}
+
+	// Short-circuit if we're already initialized, unless the caller asked us to force a rebuild of the
+	// bindings (for example because they were manually deleted after initialization was recorded), or asked
+	// us to verify the actual state rather than trusting the annotation.
+	if namespace.Annotations[initializedAnnotation] == "true" && !opts.ForceReinitialize {
+		if !opts.VerifyExisting {
+			return nil
+		}
+		accessor := policy.NewLocalRoleBindingAccessor(namespaceName, roleBindingClient)
+		upToDate, err := serviceAccountRoleBindingsUpToDate(accessor, desiredBindings)
+		if err != nil {
+			log.Error(err, "Unable to verify service account role bindings in namespace", "namespace", namespaceName)
+			return err
+		}
+		if upToDate {
+			return nil
+		}
+		log.Info("Detected missing service account role bindings despite prior initialization; re-applying", "namespace", namespaceName)
+	}
+
+	hasErrors := false
+	for _, binding := range desiredBindings {
+		addRole := &policy.RoleModificationOptions{
+			RoleName:            binding.RoleRef.Name,
+			RoleNamespace:       binding.RoleRef.Namespace,
+			RoleBindingAccessor: policy.NewLocalRoleBindingAccessor(namespaceName, roleBindingClient),
+			Subjects:            binding.Subjects,
+		}
+		if err := retry.RetryOnConflict(backoff, func() error { return addRole.AddRole() }); err != nil {
+			log.Error(err, "Could not add service accounts to role in namespace", "role", binding.RoleRef.Name, "namespace", namespaceName)
+			hasErrors = true
+		}
+	}
+
+	// If we had errors, don't register initialization so we can try again
+	if hasErrors {
+		return fmt.Errorf("could not add every service account role binding in namespace %s", namespaceName)
+	}
+
+	// Retry on conflict rather than just logging it, so the annotation still lands under concurrent updates
+	// instead of being silently dropped and the bindings needlessly re-applied on the next master start.
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := kubeClient.Core().Namespaces().Get(namespaceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.Annotations == nil {
+			current.Annotations = map[string]string{}
+		}
+		current.Annotations[initializedAnnotation] = "true"
+		_, err = kubeClient.Core().Namespaces().Update(current)
+		return err
+	})
+	if err != nil {
+		log.Error(err, "Error recording adding service account roles to namespace", "namespace", namespaceName)
+		return err
+	}
+	return nil
+}
+
+// ensureNamespaceServiceAccountRoleBindings initializes roles for service accounts in the namespace
+func (c *MasterConfig) ensureNamespaceServiceAccountRoleBindings(namespace *kapi.Namespace) {
+	opts := EnsureServiceAccountRoleBindingsOptions{
+		Namespace:             namespace,
+		InitializedAnnotation: c.serviceAccountRolesInitializedAnnotation(),
+		ForceReinitialize:     c.ForceReinitializeServiceAccountRoles,
+		VerifyExisting:        c.Options.PolicyConfig.Bootstrap.VerifyServiceAccountRoleBindings,
+		RetryBackoff:          c.serviceAccountRoleRetryBackoff(),
+		DesiredRoleBindings:   c.sharedResourcesNamespaceRoleBindings(namespace.Name),
+		Log:                   c.bootstrapLog(),
+	}
+	if err := EnsureServiceAccountRoleBindings(c.ServiceAccountRoleBindingClient(), c.bootstrapKubeClient(), namespace.Name, opts); err != nil {
+		c.bootstrapLog().Error(err, "Error ensuring service account role bindings in namespace", "namespace", namespace.Name)
+	}
+}
+
+// sharedResourcesNamespaceRoleBindings converts PolicyConfig.SharedResourcesNamespaceRoleBindings into
+// authorizationapi.RoleBinding objects scoped to namespaceName, for use as
+// EnsureServiceAccountRoleBindingsOptions.DesiredRoleBindings. It returns nil - meaning "use the default
+// project bindings" - unless namespaceName is the configured OpenShiftSharedResourcesNamespace and at least
+// one override is configured.
+func (c *MasterConfig) sharedResourcesNamespaceRoleBindings(namespaceName string) []authorizationapi.RoleBinding {
+	if namespaceName != c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace {
+		return nil
+	}
+	configured := c.Options.PolicyConfig.SharedResourcesNamespaceRoleBindings
+	if len(configured) == 0 {
+		return nil
+	}
+
+	bindings := make([]authorizationapi.RoleBinding, 0, len(configured))
+	for _, binding := range configured {
+		bindings = append(bindings, authorizationapi.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: binding.Name, Namespace: namespaceName},
+			RoleRef:    kapi.ObjectReference{Name: binding.RoleName},
+			Subjects:   binding.Subjects,
+		})
+	}
+	return bindings
+}
+
+// bootstrapOwnedSCCLabel marks an SCC as having been created by bootstrap policy initialization, so
+// pruneStaleSecurityContextConstraints can tell bootstrap-owned SCCs apart from user-created ones and never
+// touch the latter.
+const bootstrapOwnedSCCLabel = "openshift.io/bootstrap-owned"
+
+// suppressedSCCsAnnotation, when set on the OpenShift infra namespace, names a comma-separated list of
+// bootstrap SCCs that ensureDefaultSecurityContextConstraints must never (re)create, so an admin who
+// deliberately deleted a bootstrap SCC (for example anyuid, as a hardening measure) doesn't have it
+// resurrected on the next master restart.
+const suppressedSCCsAnnotation = "openshift.io/bootstrap-scc-suppress"
+
+// suppressedBootstrapSCCNames reads suppressedSCCsAnnotation off the OpenShift infra namespace and returns the
+// set of bootstrap SCC names it names. A missing namespace or annotation yields an empty set rather than an
+// error, since suppression is an opt-in hardening measure and its absence is the common case.
+func (c *MasterConfig) suppressedBootstrapSCCNames() sets.String {
+	ns := c.infraNamespace()
+	namespace, err := c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil {
+		if !kapierror.IsNotFound(err) {
+			c.bootstrapLog().Error(err, "Unable to read suppressed security context constraints; treating as none", "namespace", ns)
+		}
+		return sets.NewString()
+	}
+	raw := namespace.Annotations[suppressedSCCsAnnotation]
+	if len(raw) == 0 {
+		return sets.NewString()
+	}
+	suppressed := sets.NewString()
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			suppressed.Insert(name)
+		}
+	}
+	c.bootstrapLog().Info("Suppressing default security context constraints per admin annotation", "namespace", ns, "sccs", strings.Join(suppressed.List(), ","))
+	return suppressed
+}
+
+// augmentSCCUsersWithLabeledServiceAccounts unions users with the infra namespace service accounts matching
+// PolicyConfig.InfraSCCServiceAccountLabelSelector, added to InfraSCCServiceAccountLabelSCCName's entry (or
+// "privileged" when that's unset). It returns users unchanged when the selector is unset, or when listing
+// the service accounts fails - a transient list error here shouldn't take down SCC bootstrap over what is
+// meant to be a best-effort convenience. The union never removes an existing user.
+func (c *MasterConfig) augmentSCCUsersWithLabeledServiceAccounts(ns string, users map[string][]string) map[string][]string {
+	selector := c.Options.PolicyConfig.InfraSCCServiceAccountLabelSelector
+	if len(selector) == 0 {
+		return users
+	}
+
+	sccName := c.Options.PolicyConfig.InfraSCCServiceAccountLabelSCCName
+	if len(sccName) == 0 {
+		sccName = bootstrappolicy.SecurityContextConstraintPrivileged
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		c.bootstrapLog().Error(err, "Invalid InfraSCCServiceAccountLabelSelector; leaving SCC access unaugmented", "selector", selector)
+		return users
+	}
+
+	saList, err := c.bootstrapKubeClient().Core().ServiceAccounts(ns).List(metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		c.bootstrapLog().Error(err, "Unable to list labeled infra service accounts for SCC augmentation", "namespace", ns, "selector", selector)
+		return users
+	}
+	if len(saList.Items) == 0 {
+		return users
+	}
+
+	augmented := map[string][]string{}
+	for name, existing := range users {
+		augmented[name] = append([]string{}, existing...)
+	}
+	existingUsers := sets.NewString(augmented[sccName]...)
+	for _, sa := range saList.Items {
+		username := serviceaccount.MakeUsername(ns, sa.Name)
+		if existingUsers.Has(username) {
+			continue
+		}
+		augmented[sccName] = append(augmented[sccName], username)
+		existingUsers.Insert(username)
+	}
+
+	c.bootstrapLog().Info("Augmented security context constraint access with labeled infra service accounts", "scc", sccName, "namespace", ns, "selector", selector, "count", len(saList.Items))
+	return augmented
+}
+
+// augmentSCCGroupsWithLabeledGroups unions groups with the user.openshift.io Groups matching
+// PolicyConfig.SCCGroupLabelSelector, added to SCCGroupLabelSCCName's entry (or "privileged" when that's
+// unset). It returns groups unchanged when the selector is unset, or when listing the Groups fails - a
+// transient list error here shouldn't take down SCC bootstrap over what is meant to be a best-effort
+// convenience. The union never removes an existing group.
+func (c *MasterConfig) augmentSCCGroupsWithLabeledGroups(groups map[string][]string) map[string][]string {
+	selector := c.Options.PolicyConfig.SCCGroupLabelSelector
+	if len(selector) == 0 {
+		return groups
+	}
+
+	sccName := c.Options.PolicyConfig.SCCGroupLabelSCCName
+	if len(sccName) == 0 {
+		sccName = bootstrappolicy.SecurityContextConstraintPrivileged
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		c.bootstrapLog().Error(err, "Invalid SCCGroupLabelSelector; leaving SCC group access unaugmented", "selector", selector)
+		return groups
+	}
+
+	groupList, err := c.PrivilegedLoopbackOpenShiftClient.Groups().List(metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		c.bootstrapLog().Error(err, "Unable to list labeled groups for SCC augmentation", "selector", selector)
+		return groups
+	}
+	if len(groupList.Items) == 0 {
+		return groups
+	}
+
+	augmented := map[string][]string{}
+	for name, existing := range groups {
+		augmented[name] = append([]string{}, existing...)
+	}
+	existingGroups := sets.NewString(augmented[sccName]...)
+	for _, group := range groupList.Items {
+		if existingGroups.Has(group.Name) {
+			continue
+		}
+		augmented[sccName] = append(augmented[sccName], group.Name)
+		existingGroups.Insert(group.Name)
+	}
+
+	c.bootstrapLog().Info("Augmented security context constraint access with labeled groups", "scc", sccName, "selector", selector, "count", len(groupList.Items))
+	return augmented
+}
+
+// ensureDefaultSecurityContextConstraints creates, reconciles, and (if configured) prunes the bootstrap
+// security context constraints, returning the number of SCCs actually created on this call alongside the
+// usual error. A caller can compare created against 0 to catch a fresh cluster provisioning no SCCs at all -
+// which, since GetBootstrapSecurityContextConstraints is never expected to return an empty list, points at a
+// packaging bug rather than a legitimate steady state.
+func (c *MasterConfig) ensureDefaultSecurityContextConstraints(ctx context.Context, result *BootstrapStepResult) (int, error) {
+	ns := c.infraNamespace()
+	bootstrapSCCGroups, bootstrapSCCUsers := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCUsers = c.augmentSCCUsersWithLabeledServiceAccounts(ns, bootstrapSCCUsers)
+	bootstrapSCCGroups = c.augmentSCCGroupsWithLabeledGroups(bootstrapSCCGroups)
+	sccClient := c.sccClient()
+
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(bootstrapSCCGroups, bootstrapSCCUsers)
+	if err := applySCCPriorityOverrides(bootstrapSCCs, c.Options.PolicyConfig.SecurityContextConstraintPriorityOverrides); err != nil {
+		c.bootstrapLog().Error(err, "Refusing to create default security context constraints")
+		result.addError(err)
+		return 0, err
+	}
+	currentNames := sets.NewString()
+	for _, scc := range bootstrapSCCs {
+		currentNames.Insert(scc.Name)
+	}
+
+	// Validate every SCC before creating any of them, so a single bad definition (an invalid volume type, a
+	// malformed range) fails master startup with a clear, actionable error instead of silently leaving the
+	// cluster with a partially-provisioned security posture that only surfaces later as a create error buried
+	// in the log.
+	if err := validateBootstrapSecurityContextConstraints(bootstrapSCCs); err != nil {
+		c.bootstrapLog().Error(err, "Refusing to create default security context constraints")
+		result.addError(err)
+		return 0, err
+	}
+
+	// Read the suppression list once per run rather than once per SCC, so its lookup and log message aren't
+	// repeated needlessly for every bootstrap SCC.
+	suppressed := c.suppressedBootstrapSCCNames()
+
+	// createdNames and existingNames back the "SCC bootstrap summary" log line below and result.Names, so an
+	// admin can tell at a glance which SCCs this run actually created versus which were already present,
+	// without having to scan a separate log line per SCC.
+	var createdNames, existingNames []string
+
+	for _, scc := range bootstrapSCCs {
+		if err := ctx.Err(); err != nil {
+			c.bootstrapLog().Error(err, "Stopping default security context constraint initialization")
+			return len(createdNames), err
+		}
+		if suppressed.Has(scc.Name) {
+			c.bootstrapLog().Info("Skipping suppressed default security context constraint", "scc", scc.Name)
+			result.inc("suppressed")
+			continue
+		}
+		if scc.Labels == nil {
+			scc.Labels = map[string]string{}
+		}
+		scc.Labels[bootstrapOwnedSCCLabel] = "true"
+
+		_, err := sccClient.Create(&scc)
+		if err == nil {
+			c.bootstrapLog().Info("Created default security context constraint", "scc", scc.Name)
+			result.inc("created")
+			createdNames = append(createdNames, scc.Name)
+			c.auditBootstrapMutation("scc-create", scc.Name)
+			continue
+		}
+		if !kapierror.IsAlreadyExists(err) {
+			c.bootstrapLog().Error(err, "Unable to create default security context constraint", "scc", scc.Name)
+			result.addError(err)
+			continue
+		}
+		existingNames = append(existingNames, scc.Name)
+		if c.Options.PolicyConfig.Bootstrap.StrictSCCOwnership {
+			if err := verifyBootstrapSCCOwnership(sccClient, scc.Name); err != nil {
+				c.bootstrapLog().Error(err, "Refusing to treat existing security context constraint as bootstrap-owned", "scc", scc.Name)
+				result.addError(err)
+				continue
+			}
+		}
+		if !c.Options.PolicyConfig.Bootstrap.ReconcileSecurityContextConstraints {
+			result.inc("skipped")
+			continue
+		}
+		if err := reconcileSecurityContextConstraint(sccClient, scc); err != nil {
+			c.bootstrapLog().Error(err, "Unable to reconcile default security context constraint", "scc", scc.Name)
+			result.addError(err)
+		} else {
+			result.inc("reconciled")
+			c.auditBootstrapMutation("scc-reconcile", scc.Name)
+		}
+	}
+
+	if len(createdNames) > 0 || len(existingNames) > 0 {
+		result.setNames("created", createdNames)
+		result.setNames("existing", existingNames)
+		c.bootstrapLog().Info("SCC bootstrap summary", "created", createdNames, "existing", existingNames)
+	}
+
+	if c.Options.PolicyConfig.Bootstrap.PruneStaleSecurityContextConstraints {
+		if err := pruneStaleSecurityContextConstraints(sccClient, currentNames); err != nil {
+			c.bootstrapLog().Error(err, "Unable to prune stale bootstrap security context constraints")
+			result.addError(err)
+		}
+	}
+
+	if c.Options.PolicyConfig.Bootstrap.VerifySCCServiceAccountReferences {
+		c.verifySCCServiceAccountReferences(bootstrapSCCs, c.Options.PolicyConfig.Bootstrap.CreateMissingSCCServiceAccounts, result)
+	}
+	return len(createdNames), nil
+}
+
+// verifySCCServiceAccountReferences checks that every service-account-shaped entry in each SCC's Users list
+// (as produced by bootstrappolicy.GetBoostrapSCCAccess) refers to a service account that actually exists, so
+// an SCC never silently grants access to a principal bootstrap forgot to create. Non-service-account
+// usernames (and groups, which aren't checked here at all) are ignored, since only service accounts can be
+// verified this way. A missing reference is logged as a warning and counted on result; when createMissing is
+// true it's created instead of only logged.
+func (c *MasterConfig) verifySCCServiceAccountReferences(sccs []securityapi.SecurityContextConstraints, createMissing bool, result *BootstrapStepResult) {
+	checked := sets.NewString()
+	for _, scc := range sccs {
+		for _, username := range scc.Users {
+			ns, name, err := serviceaccount.SplitUsername(username)
+			if err != nil {
+				continue
+			}
+			key := ns + "/" + name
+			if checked.Has(key) {
+				continue
+			}
+			checked.Insert(key)
+
+			_, err = c.bootstrapKubeClient().Core().ServiceAccounts(ns).Get(name, metav1.GetOptions{})
+			if err == nil {
+				continue
+			}
+			if !kapierror.IsNotFound(err) {
+				c.bootstrapLog().Error(err, "Could not verify security context constraint service account reference", "scc", scc.Name, "namespace", ns, "serviceAccount", name)
+				continue
+			}
+
+			if !createMissing {
+				c.bootstrapLog().Info("Security context constraint references a service account that does not exist", "scc", scc.Name, "namespace", ns, "serviceAccount", name)
+				result.inc("sccServiceAccountMissing")
+				continue
+			}
+
+			sa := &kapi.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+			if _, err := c.bootstrapKubeClient().Core().ServiceAccounts(ns).Create(sa); err != nil && !kapierror.IsAlreadyExists(err) {
+				c.bootstrapLog().Error(err, "Could not create service account referenced by a security context constraint", "scc", scc.Name, "namespace", ns, "serviceAccount", name)
+				result.addError(err)
+				continue
+			}
+			c.bootstrapLog().Info("Created service account referenced by a security context constraint", "scc", scc.Name, "namespace", ns, "serviceAccount", name)
+			result.inc("sccServiceAccountCreated")
+		}
+	}
+}
+
+// ReconcileSecurityContextConstraints reconciles bootstrap security context constraints against their current
+// cluster state, creating any that are missing and updating the rest to match the bootstrap definition. With no
+// names given it reconciles every bootstrap SCC; given specific names it reconciles only those, so an admin can
+// hot-fix a single SCC (for example "restricted") via the CLI without touching the rest of the bootstrap set or
+// restarting the master. An unknown name produces an error listing the valid bootstrap SCC names.
+func (c *MasterConfig) ReconcileSecurityContextConstraints(names ...string) error {
+	ns := c.infraNamespace()
+	bootstrapSCCGroups, bootstrapSCCUsers := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCUsers = c.augmentSCCUsersWithLabeledServiceAccounts(ns, bootstrapSCCUsers)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(bootstrapSCCGroups, bootstrapSCCUsers)
+	if err := applySCCPriorityOverrides(bootstrapSCCs, c.Options.PolicyConfig.SecurityContextConstraintPriorityOverrides); err != nil {
+		return err
+	}
+
+	byName := map[string]securityapi.SecurityContextConstraints{}
+	validNames := sets.NewString()
+	for _, scc := range bootstrapSCCs {
+		byName[scc.Name] = scc
+		validNames.Insert(scc.Name)
+	}
+
+	toReconcile := bootstrapSCCs
+	if len(names) > 0 {
+		unknown := []string{}
+		toReconcile = nil
+		for _, name := range names {
+			scc, ok := byName[name]
+			if !ok {
+				unknown = append(unknown, name)
+				continue
+			}
+			toReconcile = append(toReconcile, scc)
+		}
+		if len(unknown) > 0 {
+			return fmt.Errorf("unknown security context constraint(s) %s; valid names are %s", strings.Join(unknown, ", "), strings.Join(validNames.List(), ", "))
+		}
+	}
+
+	if err := validateBootstrapSecurityContextConstraints(toReconcile); err != nil {
+		return err
+	}
+
+	sccClient := c.sccClient()
+	errs := []error{}
+	for _, scc := range toReconcile {
+		if scc.Labels == nil {
+			scc.Labels = map[string]string{}
+		}
+		scc.Labels[bootstrapOwnedSCCLabel] = "true"
+
+		if _, err := sccClient.Create(&scc); err == nil {
+			c.bootstrapLog().Info("Created default security context constraint", "scc", scc.Name)
+			c.auditBootstrapMutation("scc-create", scc.Name)
+			continue
+		} else if !kapierror.IsAlreadyExists(err) {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := reconcileSecurityContextConstraint(sccClient, scc); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c.auditBootstrapMutation("scc-reconcile", scc.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// SCCReconcileAction is what PlanSecurityContextConstraintReconcile determined a bootstrap SCC needs:
+// creation, an update to bring it back in line with the bootstrap definition, or no change at all.
+type SCCReconcileAction string
+
+const (
+	SCCReconcileActionCreate SCCReconcileAction = "create"
+	SCCReconcileActionUpdate SCCReconcileAction = "update"
+	SCCReconcileActionNone   SCCReconcileAction = "none"
+)
+
+// SCCReconcilePlanEntry is one bootstrap SCC's computed reconcile outcome. ChangedFields is only populated
+// when Action is SCCReconcileActionUpdate.
+type SCCReconcilePlanEntry struct {
+	Name          string
+	Action        SCCReconcileAction
+	ChangedFields []string
+}
+
+// SCCReconcilePlan is the read-only outcome of PlanSecurityContextConstraintReconcile: what
+// ReconcileSecurityContextConstraints would do to each bootstrap SCC, without actually doing it. It's the SCC
+// analogue of PolicyDiff, meant to back an admin-facing "what would bootstrap change" report.
+type SCCReconcilePlan struct {
+	Entries []SCCReconcilePlanEntry
+}
+
+// PlanSecurityContextConstraintReconcile computes, for each named bootstrap SCC (or all of them with no names
+// given), whether ReconcileSecurityContextConstraints would create it, update it, or leave it untouched - and
+// for an update, which fields would change - without creating, updating, or deleting anything. It shares
+// sccReconcileUpdate with reconcileSecurityContextConstraint so the plan can never disagree with what an
+// actual reconcile would do. An unknown name produces an error listing the valid bootstrap SCC names, matching
+// ReconcileSecurityContextConstraints.
+func (c *MasterConfig) PlanSecurityContextConstraintReconcile(names ...string) (*SCCReconcilePlan, error) {
+	ns := c.infraNamespace()
+	bootstrapSCCGroups, bootstrapSCCUsers := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCUsers = c.augmentSCCUsersWithLabeledServiceAccounts(ns, bootstrapSCCUsers)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(bootstrapSCCGroups, bootstrapSCCUsers)
+	if err := applySCCPriorityOverrides(bootstrapSCCs, c.Options.PolicyConfig.SecurityContextConstraintPriorityOverrides); err != nil {
+		return nil, err
+	}
+
+	byName := map[string]securityapi.SecurityContextConstraints{}
+	validNames := sets.NewString()
+	for _, scc := range bootstrapSCCs {
+		byName[scc.Name] = scc
+		validNames.Insert(scc.Name)
+	}
+
+	toPlan := bootstrapSCCs
+	if len(names) > 0 {
+		unknown := []string{}
+		toPlan = nil
+		for _, name := range names {
+			scc, ok := byName[name]
+			if !ok {
+				unknown = append(unknown, name)
+				continue
+			}
+			toPlan = append(toPlan, scc)
+		}
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("unknown security context constraint(s) %s; valid names are %s", strings.Join(unknown, ", "), strings.Join(validNames.List(), ", "))
+		}
+	}
+
+	if err := validateBootstrapSecurityContextConstraints(toPlan); err != nil {
+		return nil, err
+	}
+
+	sccClient := c.sccClient()
+	plan := &SCCReconcilePlan{}
+	for _, scc := range toPlan {
+		existing, err := sccClient.Get(scc.Name, metav1.GetOptions{})
+		if kapierror.IsNotFound(err) {
+			plan.Entries = append(plan.Entries, SCCReconcilePlanEntry{Name: scc.Name, Action: SCCReconcileActionCreate})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, changedFields := sccReconcileUpdate(*existing, scc)
+		if len(changedFields) == 0 {
+			plan.Entries = append(plan.Entries, SCCReconcilePlanEntry{Name: scc.Name, Action: SCCReconcileActionNone})
+			continue
+		}
+		plan.Entries = append(plan.Entries, SCCReconcilePlanEntry{Name: scc.Name, Action: SCCReconcileActionUpdate, ChangedFields: changedFields})
+	}
+	return plan, nil
+}
+
+// SCCHealthStatus is the outcome of SecurityContextConstraintsStatus: whether every bootstrap security
+// context constraint currently exists and matches its bootstrap definition.
+type SCCHealthStatus string
+
+const (
+	SCCHealthStatusHealthy  SCCHealthStatus = "healthy"
+	SCCHealthStatusDegraded SCCHealthStatus = "degraded"
+)
+
+// SCCIntegrityStatus is the result of SecurityContextConstraintsStatus. Missing lists bootstrap SCCs that
+// don't exist at all; Drifted lists ones that exist but differ from the bootstrap definition. Status is
+// SCCHealthStatusDegraded whenever either is non-empty.
+type SCCIntegrityStatus struct {
+	Status  SCCHealthStatus
+	Missing []string
+	Drifted []string
+}
+
+// SecurityContextConstraintsStatus reports whether all bootstrap security context constraints currently exist
+// and match their bootstrap definitions, for monitoring to alert on. It's read-only - unlike
+// ReconcileSecurityContextConstraints, it never creates, updates, or deletes anything - and it shares
+// PlanSecurityContextConstraintReconcile so it can never disagree with what an actual reconcile would find.
+func (c *MasterConfig) SecurityContextConstraintsStatus() (*SCCIntegrityStatus, error) {
+	plan, err := c.PlanSecurityContextConstraintReconcile()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SCCIntegrityStatus{Status: SCCHealthStatusHealthy}
+	for _, entry := range plan.Entries {
+		switch entry.Action {
+		case SCCReconcileActionCreate:
+			status.Missing = append(status.Missing, entry.Name)
+		case SCCReconcileActionUpdate:
+			status.Drifted = append(status.Drifted, entry.Name)
+		}
+	}
+	if len(status.Missing) > 0 || len(status.Drifted) > 0 {
+		status.Status = SCCHealthStatusDegraded
+	}
+	return status, nil
+}
+
+// applySCCPriorityOverrides applies PolicyConfig.SecurityContextConstraintPriorityOverrides to the matching
+// entries of sccs in place, letting an operator adjust a bootstrap SCC's relative priority (for example,
+// making a custom SCC win over "anyuid") without editing the embedded bootstrap definitions. It's called
+// before sccs is compared against cluster state, so the override is what gets created, reconciled, and
+// planned alike. Every override key must name an SCC present in sccs; an unrecognized name is an error
+// listing the valid names, so a typo is caught at bootstrap time instead of silently having no effect.
+func applySCCPriorityOverrides(sccs []securityapi.SecurityContextConstraints, overrides map[string]*int32) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	validNames := sets.NewString()
+	for i := range sccs {
+		validNames.Insert(sccs[i].Name)
+	}
+
+	unknown := sets.NewString()
+	for name := range overrides {
+		if !validNames.Has(name) {
+			unknown.Insert(name)
+		}
+	}
+	if unknown.Len() > 0 {
+		return fmt.Errorf("unknown security context constraint(s) in priority overrides: %s; valid names are %s", strings.Join(unknown.List(), ", "), strings.Join(validNames.List(), ", "))
+	}
+
+	for i := range sccs {
+		if priority, ok := overrides[sccs[i].Name]; ok {
+			sccs[i].Priority = priority
+		}
+	}
+	return nil
+}
+
+// validateBootstrapSecurityContextConstraints runs the same validation the apiserver would apply on create
+// against every bootstrap SCC up front, returning a single aggregate error naming every invalid SCC rather
+// than letting the create loop discover them one at a time.
+func validateBootstrapSecurityContextConstraints(sccs []securityapi.SecurityContextConstraints) error {
+	errs := []error{}
+	for i := range sccs {
+		if fieldErrs := securityvalidation.ValidateSecurityContextConstraints(&sccs[i]); len(fieldErrs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid security context constraints %q: %v", sccs[i].Name, fieldErrs.ToAggregate()))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// pruneStaleSecurityContextConstraints deletes bootstrap-owned SCCs that are no longer part of
+// currentNames, so SCCs that were bootstrapped by an older version and later dropped don't linger forever.
+// It only ever considers SCCs carrying bootstrapOwnedSCCLabel, so user-created SCCs are never touched.
+func pruneStaleSecurityContextConstraints(sccClient legacyclient.SecurityContextConstraintInterface, currentNames sets.String) error {
+	bootstrapOwned, err := sccClient.List(metav1.ListOptions{LabelSelector: bootstrapOwnedSCCLabel + "=true"})
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, scc := range bootstrapOwned.Items {
+		if currentNames.Has(scc.Name) {
+			continue
+		}
+		if err := sccClient.Delete(scc.Name); err != nil && !kapierror.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		glog.V(2).Infof("Pruned stale bootstrap security context constraint %s", scc.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// verifyBootstrapSCCOwnership returns an error if the SCC named sccName already exists but lacks
+// bootstrapOwnedSCCLabel, meaning some other actor created or replaced it - the label is only ever stamped by
+// ensureDefaultSecurityContextConstraints itself. Used by BootstrapOptions.StrictSCCOwnership to flag
+// tampering instead of silently reconciling or skipping an SCC bootstrap didn't create.
+func verifyBootstrapSCCOwnership(sccClient legacyclient.SecurityContextConstraintInterface, sccName string) error {
+	existing, err := sccClient.Get(sccName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if existing.Labels[bootstrapOwnedSCCLabel] != "true" {
+		return fmt.Errorf("security context constraint %q already exists but is not labeled %s=true; it may have been created or replaced by a non-bootstrap actor", sccName, bootstrapOwnedSCCLabel)
+	}
+	return nil
+}
+
+// reconcileSecurityContextConstraint brings an existing SCC's priority, volumes, allowed capabilities, and
+// user/group lists in line with the bootstrap definition. The merge policy mirrors reconcileNamespacedRole's
+// union-of-rules approach: Priority, a scalar with no meaningful way to "merge", is set authoritatively from
+// the bootstrap definition, while list-type allowlists an admin might hand-edit - Volumes and
+// AllowedCapabilities, in this API version - are unioned so admin-added entries survive the reconcile, exactly
+// like Users and Groups are. Strategy fields (SELinuxContext, RunAsUser, and the like) are neither compared nor
+// updated.
+func reconcileSecurityContextConstraint(sccClient legacyclient.SecurityContextConstraintInterface, bootstrapSCC securityapi.SecurityContextConstraints) error {
+	existing, err := sccClient.Get(bootstrapSCC.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	updated, changedFields := sccReconcileUpdate(*existing, bootstrapSCC)
+	if len(changedFields) == 0 {
+		return nil
+	}
+
+	_, err = sccClient.Update(&updated)
+	if err == nil {
+		glog.V(2).Infof("Reconciled default security context constraint %s", bootstrapSCC.Name)
+	}
+	return err
+}
+
+// sccReconcileUpdate computes the object reconcileSecurityContextConstraint would persist for existing brought
+// in line with bootstrapSCC, along with the names of the fields that differ. It never talks to the API server,
+// so it backs both reconcileSecurityContextConstraint (which applies the result) and
+// PlanSecurityContextConstraintReconcile (which only reports it) off a single source of truth for what
+// "changed" means.
+func sccReconcileUpdate(existing, bootstrapSCC securityapi.SecurityContextConstraints) (securityapi.SecurityContextConstraints, []string) {
+	updated := existing
+	var changedFields []string
+
+	if !reflect.DeepEqual(existing.Priority, bootstrapSCC.Priority) {
+		updated.Priority = bootstrapSCC.Priority
+		changedFields = append(changedFields, "priority")
+	}
+	if mergedVolumes, volumesChanged := unionFSTypes(existing.Volumes, bootstrapSCC.Volumes); volumesChanged {
+		updated.Volumes = mergedVolumes
+		changedFields = append(changedFields, "volumes")
+	}
+	if mergedCapabilities, capabilitiesChanged := unionCapabilities(existing.AllowedCapabilities, bootstrapSCC.AllowedCapabilities); capabilitiesChanged {
+		updated.AllowedCapabilities = mergedCapabilities
+		changedFields = append(changedFields, "allowedCapabilities")
+	}
+	if mergedUsers, userChanged := unionStrings(existing.Users, bootstrapSCC.Users); userChanged {
+		updated.Users = mergedUsers
+		changedFields = append(changedFields, "users")
+	}
+	if mergedGroups, groupChanged := unionStrings(existing.Groups, bootstrapSCC.Groups); groupChanged {
+		updated.Groups = mergedGroups
+		changedFields = append(changedFields, "groups")
+	}
+
+	return updated, changedFields
+}
+
+// unionStrings returns the union of existing and desired (preserving existing's admin-added entries), and
+// whether the result differs from existing.
+func unionStrings(existing, desired []string) ([]string, bool) {
+	have := sets.NewString(existing...)
+	union := sets.NewString(existing...)
+	union.Insert(desired...)
+	if union.Len() == have.Len() {
+		return existing, false
+	}
+	return union.List(), true
+}
+
+// unionFSTypes returns the union of existing and desired (preserving existing's admin-added entries), and
+// whether the result differs from existing. Order is not preserved - the merged list is sorted - since neither
+// caller relies on FSType ordering.
+func unionFSTypes(existing, desired []securityapi.FSType) ([]securityapi.FSType, bool) {
+	have := sets.NewString()
+	for _, fsType := range existing {
+		have.Insert(string(fsType))
+	}
+	union := sets.NewString(have.UnsortedList()...)
+	for _, fsType := range desired {
+		union.Insert(string(fsType))
+	}
+	if union.Len() == have.Len() {
+		return existing, false
+	}
+	merged := make([]securityapi.FSType, 0, union.Len())
+	for _, name := range union.List() {
+		merged = append(merged, securityapi.FSType(name))
+	}
+	return merged, true
+}
+
+// unionCapabilities returns the union of existing and desired (preserving existing's admin-added entries), and
+// whether the result differs from existing. Order is not preserved - the merged list is sorted - since neither
+// caller relies on capability ordering.
+func unionCapabilities(existing, desired []kapi.Capability) ([]kapi.Capability, bool) {
+	have := sets.NewString()
+	for _, capability := range existing {
+		have.Insert(string(capability))
+	}
+	union := sets.NewString(have.UnsortedList()...)
+	for _, capability := range desired {
+		union.Insert(string(capability))
+	}
+	if union.Len() == have.Len() {
+		return existing, false
+	}
+	merged := make([]kapi.Capability, 0, union.Len())
+	for _, name := range union.List() {
+		merged = append(merged, kapi.Capability(name))
+	}
+	return merged, true
+}
+
+// recordBootstrapEvent emits an Event via BootstrapEventRecorder, targeting the master's infra namespace so
+// the record shows up alongside the rest of the cluster's bootstrap activity. It is a no-op when no recorder
+// has been configured, which keeps it safe to call from unit tests that construct a bare MasterConfig.
+func (c *MasterConfig) recordBootstrapEvent(eventtype, reason, messageFmt string, args ...interface{}) {
+	if c.BootstrapEventRecorder == nil {
+		return
+	}
+	ref := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: c.infraNamespace()}}
+	c.BootstrapEventRecorder.Eventf(ref, eventtype, reason, messageFmt, args...)
+}
+
+// createNamespacedRoleWithRetry creates role using roleClient, retrying conflicts and transient errors with
+// defaultBootstrapRetryBackoff and treating IsAlreadyExists as success, so a brief conflict or timeout during
+// startup doesn't permanently drop a namespaced bootstrap role the way a single failed Create would.
+func createNamespacedRoleWithRetry(roleClient osclient.RoleInterface, role *authorizationapi.Role) error {
+	var lastErr error
+	err := utilwait.ExponentialBackoff(defaultBootstrapRetryBackoff, func() (bool, error) {
+		_, createErr := roleClient.Create(role)
+		if createErr == nil || kapierror.IsAlreadyExists(createErr) {
+			return true, nil
+		}
+		lastErr = createErr
+		if !isTransientCreateError(createErr) && !kapierror.IsConflict(createErr) {
+			return false, createErr
+		}
+		return false, nil
+	})
+	if err != nil && err != lastErr {
+		return lastErr
+	}
+	return err
+}
+
+// reconcileNamespacedRole creates role if it doesn't exist yet, or updates it in place when its rules have
+// drifted from the bootstrap definition, unioning in any rules an admin added by hand so they survive. When
+// dryRun is true, the planned Create/Update is described on out instead of being sent to the apiserver.
+func reconcileNamespacedRole(roleClient osclient.RoleInterface, role *authorizationapi.Role, dryRun bool, out io.Writer) error {
+	existing, err := roleClient.Get(role.Name, metav1.GetOptions{})
+	if kapierror.IsNotFound(err) {
+		if dryRun {
+			fmt.Fprintf(out, "would create role %s/%s\n", role.Namespace, role.Name)
+			return nil
+		}
+		return createNamespacedRoleWithRetry(roleClient, role)
+	}
+	if err != nil {
+		return err
+	}
+
+	mergedRules := unionPolicyRules(existing.Rules, role.Rules)
+	if reflect.DeepEqual(existing.Rules, mergedRules) {
+		return nil
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "would update role %s/%s: rules %v -> %v\n", role.Namespace, role.Name, existing.Rules, mergedRules)
+		return nil
+	}
+
+	existing.Rules = mergedRules
+	_, err = roleClient.Update(existing)
+	return err
+}
+
+// createNamespacedRoleBindingWithRetry is the RoleBinding analogue of createNamespacedRoleWithRetry.
+func createNamespacedRoleBindingWithRetry(roleBindingClient osclient.RoleBindingInterface, roleBinding *authorizationapi.RoleBinding) error {
+	var lastErr error
+	err := utilwait.ExponentialBackoff(defaultBootstrapRetryBackoff, func() (bool, error) {
+		_, createErr := roleBindingClient.Create(roleBinding)
+		if createErr == nil || kapierror.IsAlreadyExists(createErr) {
+			return true, nil
+		}
+		lastErr = createErr
+		if !isTransientCreateError(createErr) && !kapierror.IsConflict(createErr) {
+			return false, createErr
+		}
+		return false, nil
+	})
+	if err != nil && err != lastErr {
+		return lastErr
+	}
+	return err
+}
+
+// reconcileNamespacedRoleBinding mirrors reconcileNamespacedRole for RoleBindings, unioning in Subjects an
+// admin added by hand rather than overwriting them. RoleRef is immutable on Update, so a plain Update can't
+// apply a changed RoleRef; when recreateOnRoleRefChange is true, a RoleRef mismatch instead deletes and
+// recreates the binding with the desired RoleRef, preserving the merged Subjects. Callers should only pass
+// recreateOnRoleRefChange for bootstrap-owned bindings - deleting and recreating an admin-managed binding out
+// from under them would be a surprising thing for a reconcile to do. When dryRun is true, the planned
+// Create/Update/recreate is described on out instead of being sent to the apiserver.
+func reconcileNamespacedRoleBinding(roleBindingClient osclient.RoleBindingInterface, roleBinding *authorizationapi.RoleBinding, recreateOnRoleRefChange, dryRun bool, out io.Writer) error {
+	existing, err := roleBindingClient.Get(roleBinding.Name, metav1.GetOptions{})
+	if kapierror.IsNotFound(err) {
+		if dryRun {
+			fmt.Fprintf(out, "would create rolebinding %s/%s\n", roleBinding.Namespace, roleBinding.Name)
+			return nil
+		}
+		return createNamespacedRoleBindingWithRetry(roleBindingClient, roleBinding)
+	}
+	if err != nil {
+		return err
+	}
+
+	mergedSubjects := unionSubjects(existing.Subjects, roleBinding.Subjects)
+	roleRefChanged := !reflect.DeepEqual(existing.RoleRef, roleBinding.RoleRef)
+	if reflect.DeepEqual(existing.Subjects, mergedSubjects) && !roleRefChanged {
+		return nil
+	}
+
+	if roleRefChanged && recreateOnRoleRefChange {
+		if dryRun {
+			fmt.Fprintf(out, "would recreate rolebinding %s/%s: roleRef %v -> %v\n", roleBinding.Namespace, roleBinding.Name, existing.RoleRef, roleBinding.RoleRef)
+			return nil
+		}
+		if err := roleBindingClient.Delete(roleBinding.Name); err != nil {
+			return err
+		}
+		roleBinding.Subjects = mergedSubjects
+		return createNamespacedRoleBindingWithRetry(roleBindingClient, roleBinding)
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "would update rolebinding %s/%s: subjects %v -> %v\n", roleBinding.Namespace, roleBinding.Name, existing.Subjects, mergedSubjects)
+		return nil
+	}
+
+	existing.Subjects = mergedSubjects
+	existing.RoleRef = roleBinding.RoleRef
+	_, err = roleBindingClient.Update(existing)
+	return err
+}
+
+// unionPolicyRules returns existing plus any of desired's rules that aren't already present in existing.
+func unionPolicyRules(existing, desired []authorizationapi.PolicyRule) []authorizationapi.PolicyRule {
+	merged := append([]authorizationapi.PolicyRule{}, existing...)
+	for _, rule := range desired {
+		found := false
+		for _, have := range existing {
+			if reflect.DeepEqual(have, rule) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// unionSubjects returns existing plus any of desired's subjects that aren't already present in existing.
+func unionSubjects(existing, desired []kapi.ObjectReference) []kapi.ObjectReference {
+	merged := append([]kapi.ObjectReference{}, existing...)
+	for _, subject := range desired {
+		found := false
+		for _, have := range existing {
+			if reflect.DeepEqual(have, subject) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, subject)
+		}
+	}
+	return merged
+}
+
+// validateBootstrapPolicyFile reads and decodes the bootstrap policy file at path, confirming it parses into
+// a template of policy objects and that the template is non-empty. It's meant to be called before handing
+// the file to admin.OverwriteBootstrapPolicy, so a malformed or empty file is rejected with a descriptive
+// error instead of silently wiping or corrupting cluster policy.
+func validateBootstrapPolicyFile(path string) error {
+	mapper := kapi.Registry.RESTMapper()
+	typer := kapi.Scheme
+	clientMapper := resource.ClientMapperFunc(func(mapping *meta.RESTMapping) (resource.RESTClient, error) {
+		return nil, nil
+	})
+
+	r := resource.NewBuilder(mapper, resource.SimpleCategoryExpander{}, typer, clientMapper, kapi.Codecs.UniversalDecoder()).
+		FilenameParam(false, &resource.FilenameOptions{Recursive: false, Filenames: []string{path}}).
+		Flatten().
+		Do()
+	if r.Err() != nil {
+		return fmt.Errorf("unable to read bootstrap policy file %q: %v", path, r.Err())
+	}
+
+	objectCount := 0
+	if err := r.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+		template, ok := info.Object.(*templateapi.Template)
+		if !ok {
+			return fmt.Errorf("expected a template of policy objects, got %T", info.Object)
+		}
+		objectCount += len(template.Objects)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("unable to parse bootstrap policy file %q: %v", path, err)
+	}
+	if objectCount == 0 {
+		return fmt.Errorf("bootstrap policy file %q does not contain any policy objects", path)
+	}
+	return nil
+}
+
+// ComponentAuthorizationRulesOptions groups the dependencies ReconcileComponentAuthorizationRules needs,
+// pulled out of MasterConfig so the reconciliation ensureComponentAuthorizationRules performs at master
+// startup can also be driven on demand - for example from an `oc adm` command - against a live cluster
+// without a running master. EventRecorder and Log may be left nil; a nil EventRecorder skips event
+// recording and a nil Log falls back to glogBootstrapLogger, since neither is meaningful outside of a
+// running master.
+type ComponentAuthorizationRulesOptions struct {
+	ClusterPolicyRegistry clusterpolicyregistry.Registry
+	RESTOptionsGetter     restoptions.Getter
+	OpenShiftClient       osclient.Interface
+
+	// BootstrapPolicyFile is the path to the bootstrap policy template to create cluster policy from. If
+	// empty, resolveBootstrapPolicyFile renders the embedded default bootstrap policy (see
+	// admin.NewBootstrapPolicyTemplate) instead, scoped to OpenShiftSharedResourcesNamespace.
+	BootstrapPolicyFile               string
+	OpenShiftSharedResourcesNamespace string
+	OverwriteBootstrapPolicyIfMissing bool
+	// ClusterPolicyMissingRecheckDelay bounds how long ReconcileComponentAuthorizationRules waits before
+	// re-issuing GetClusterPolicy after an initial NotFound, to rule out a transient error being misreported
+	// as NotFound before triggering OverwriteBootstrapPolicyIfMissing's destructive overwrite. Zero uses
+	// defaultClusterPolicyMissingRecheckDelay.
+	ClusterPolicyMissingRecheckDelay time.Duration
+	// ReconcileNamespacedRoles decides, given whether the cluster policy was found missing, whether namespaced
+	// bootstrap roles/rolebindings should be reconciled. It's a function rather than a precomputed bool so the
+	// decision can be made from the same missing/found determination this function's own GetClusterPolicy call
+	// produces, instead of requiring a caller to duplicate that Get up front. A nil func always reconciles.
+	ReconcileNamespacedRoles func(clusterPolicyMissing bool) bool
+	RolesToReconcile         []string
+	DryRun                   bool
+	Out                      io.Writer
+
+	EventRecorder func(eventtype, reason, messageFmt string, args ...interface{})
+	Log           BootstrapLogger
+
+	// ClassifyLoopbackAuthFailure, if set, is called with a Forbidden/Unauthorized reconcile error before
+	// it's recorded, giving the caller (normally MasterConfig.classifyLoopbackAuthFailure) a chance to
+	// escalate it to ErrLoopbackUnauthorized once enough of these have been seen. A nil func leaves the error
+	// unchanged.
+	ClassifyLoopbackAuthFailure func(err error) error
+
+	// RBACClusterRoles, when set, is used by detectAuthorizationMode to probe whether cluster roles already
+	// live in native rbac storage rather than being materialized from a legacy ClusterPolicy object. Left
+	// nil (the default MasterConfig.ensureComponentAuthorizationRules wiring), the rbac signal is treated as
+	// absent and ReconcileComponentAuthorizationRules behaves exactly as it always has - this check is meant
+	// to be opted into by a migration tool or admin command that specifically needs to detect a
+	// partway-migrated cluster, not run unconditionally on every master startup.
+	RBACClusterRoles rbacclient.ClusterRolesGetter
+}
+
+// classifyLoopbackAuthFailure applies options.ClassifyLoopbackAuthFailure to err if set, otherwise returns
+// err unchanged.
+func (options ComponentAuthorizationRulesOptions) classifyLoopbackAuthFailure(err error) error {
+	if options.ClassifyLoopbackAuthFailure == nil {
+		return err
+	}
+	return options.ClassifyLoopbackAuthFailure(err)
+}
+
+// getClusterPolicyWithRetry fetches the cluster policy singleton, retrying with defaultBootstrapRetryBackoff
+// whenever GetClusterPolicy fails with a transient error, so a one-off etcd read failure can't be misread as
+// "missing" (skipping bootstrap policy creation entirely) the way a bare, unretried Get would. It distinguishes
+// three outcomes: the policy exists (returned non-nil, missing false), the policy genuinely doesn't exist yet
+// (missing true, err nil), or every retry was exhausted by a transient error, or a non-transient error other
+// than NotFound occurred (a non-nil err, in which case the caller should give up rather than guess).
+func getClusterPolicyWithRetry(ctx apirequest.Context, registry clusterpolicyregistry.Registry) (policy *authorizationapi.ClusterPolicy, missing bool, err error) {
+	var lastErr error
+	if backoffErr := utilwait.ExponentialBackoff(defaultBootstrapRetryBackoff, func() (bool, error) {
+		var getErr error
+		policy, getErr = registry.GetClusterPolicy(ctx, authorizationapi.PolicyName, &metav1.GetOptions{})
+		lastErr = getErr
+		if getErr == nil || kapierror.IsNotFound(getErr) {
+			return true, nil
+		}
+		if !isTransientCreateError(getErr) {
+			return false, getErr
+		}
+		return false, nil
+	}); backoffErr != nil {
+		return nil, false, lastErr
+	}
+	return policy, kapierror.IsNotFound(lastErr), nil
+}
+
+// defaultClusterPolicyMissingRecheckDelay is the delay confirmClusterPolicyMissing waits before re-issuing
+// GetClusterPolicy, used whenever ComponentAuthorizationRulesOptions.ClusterPolicyMissingRecheckDelay is
+// left at its zero value.
+const defaultClusterPolicyMissingRecheckDelay = 2 * time.Second
+
+// confirmClusterPolicyMissing double-checks an initial NotFound GetClusterPolicy result by waiting and
+// re-issuing the Get, returning false if the recheck finds cluster policy present after all. This exists
+// because a transient error (an apiserver blip, a slow etcd) can be misreported as NotFound by some
+// clients, and treating that as "missing" would trigger OverwriteBootstrapPolicyIfMissing's destructive
+// overwrite against policy that's actually still there.
+func confirmClusterPolicyMissing(options ComponentAuthorizationRulesOptions, reqCtx apirequest.Context) bool {
+	delay := options.ClusterPolicyMissingRecheckDelay
+	if delay == 0 {
+		delay = defaultClusterPolicyMissingRecheckDelay
+	}
+	time.Sleep(delay)
+	_, err := options.ClusterPolicyRegistry.GetClusterPolicy(reqCtx, authorizationapi.PolicyName, &metav1.GetOptions{})
+	return kapierror.IsNotFound(err)
+}
+
+// bootstrapPolicyChecksumAnnotation records the sha256 checksum, hex-encoded, of the bootstrap policy file
+// that was last applied to the cluster policy object. reapplyBootstrapPolicyFileIfChanged compares against it
+// to tell whether BootstrapPolicyFile has changed since the last time it was applied, so it can skip the
+// expensive OverwriteBootstrapPolicy call when nothing changed.
+const bootstrapPolicyChecksumAnnotation = "policy.openshift.io/bootstrap-policy-checksum"
+
+// bootstrapPolicyFileChecksum returns the sha256 checksum of the bootstrap policy file at path, hex-encoded.
+func bootstrapPolicyFileChecksum(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// reapplyBootstrapPolicyFileIfChanged re-applies BootstrapPolicyFile over the already-present cluster policy
+// when the file's checksum no longer matches bootstrapPolicyChecksumAnnotation on existingPolicy, and records
+// the new checksum on success. When the checksum matches, it's a no-op: the file hasn't changed since it was
+// last applied, so redoing the (expensive) overwrite would accomplish nothing. A missing BootstrapPolicyFile
+// or a checksum/read failure is treated the same as "unchanged" - reapplying is only ever done on the strength
+// of a confirmed diff.
+func reapplyBootstrapPolicyFileIfChanged(reqCtx apirequest.Context, options ComponentAuthorizationRulesOptions, existingPolicy *authorizationapi.ClusterPolicy, recordEvent func(eventtype, reason, messageFmt string, args ...interface{})) {
+	if len(options.BootstrapPolicyFile) == 0 {
+		return
+	}
+
+	checksum, err := bootstrapPolicyFileChecksum(options.BootstrapPolicyFile)
+	if err != nil {
+		options.Log.Error(err, "Unable to checksum bootstrap policy file; leaving cluster policy untouched", "file", options.BootstrapPolicyFile)
+		return
+	}
+
+	if existingPolicy != nil && existingPolicy.Annotations[bootstrapPolicyChecksumAnnotation] == checksum {
+		glog.V(4).Infof("Bootstrap policy file %s is unchanged since it was last applied; skipping overwrite", options.BootstrapPolicyFile)
+		return
+	}
+
+	options.Log.Info("Bootstrap policy file changed since it was last applied; re-applying", "file", options.BootstrapPolicyFile)
+	if err := validateBootstrapPolicyFile(options.BootstrapPolicyFile); err != nil {
+		options.Log.Error(err, "Refusing to re-apply bootstrap policy from invalid file", "file", options.BootstrapPolicyFile)
+		recordEvent(kapi.EventTypeWarning, "BootstrapPolicyReapplyFailed", "Failed to re-apply bootstrap policy from %v: %v", options.BootstrapPolicyFile, err)
+		return
+	}
+	if err := admin.OverwriteBootstrapPolicy(options.RESTOptionsGetter, options.BootstrapPolicyFile, admin.CreateBootstrapPolicyFileFullCommand, true, ioutil.Discard); err != nil {
+		options.Log.Error(err, "Error re-applying bootstrap policy", "file", options.BootstrapPolicyFile)
+		recordEvent(kapi.EventTypeWarning, "BootstrapPolicyReapplyFailed", "Failed to re-apply bootstrap policy from %v: %v", options.BootstrapPolicyFile, err)
+		return
+	}
+	recordEvent(kapi.EventTypeNormal, "BootstrapPolicyReapplied", "Re-applied bootstrap policy from %v", options.BootstrapPolicyFile)
+
+	reapplied, err := options.ClusterPolicyRegistry.GetClusterPolicy(reqCtx, authorizationapi.PolicyName, &metav1.GetOptions{})
+	if err != nil {
+		options.Log.Error(err, "Unable to record bootstrap policy checksum after re-applying", "file", options.BootstrapPolicyFile)
+		return
+	}
+	if reapplied.Annotations == nil {
+		reapplied.Annotations = map[string]string{}
+	}
+	reapplied.Annotations[bootstrapPolicyChecksumAnnotation] = checksum
+	if err := options.ClusterPolicyRegistry.UpdateClusterPolicy(reqCtx, reapplied); err != nil {
+		options.Log.Error(err, "Unable to record bootstrap policy checksum after re-applying", "file", options.BootstrapPolicyFile)
+	}
+}
+
+// resolveBootstrapPolicyFile returns options.BootstrapPolicyFile and a no-op cleanup func when it's set.
+// When it's empty, it renders the embedded default bootstrap policy (see admin.NewBootstrapPolicyTemplate),
+// scoped to options.OpenShiftSharedResourcesNamespace, to a temp file and returns that path along with a
+// cleanup func the caller must invoke once done with it, so validateBootstrapPolicyFile and
+// admin.OverwriteBootstrapPolicy - both built around resource.Builder's FilenameParam - need no in-memory entry
+// point of their own. The fallback applies to callers of ReconcileComponentAuthorizationRules that leave
+// BootstrapPolicyFile empty; EnsureBootstrapPolicy does not reach it, because validateBootstrapNamespaceConfig
+// rejects an empty PolicyConfig.BootstrapPolicyFile before any step runs.
+func resolveBootstrapPolicyFile(options ComponentAuthorizationRulesOptions) (path string, cleanup func(), err error) {
+	if len(options.BootstrapPolicyFile) > 0 {
+		return options.BootstrapPolicyFile, func() {}, nil
+	}
+
+	policyJSON, err := admin.BootstrapPolicyTemplateJSON(options.OpenShiftSharedResourcesNamespace)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpFile, err := ioutil.TempFile("", "bootstrap-policy")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { os.Remove(tmpFile.Name()) }
+
+	if _, err := tmpFile.Write(policyJSON); err != nil {
+		tmpFile.Close()
+		cleanup()
+		return "", nil, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return tmpFile.Name(), cleanup, nil
+}
+
+// AuthorizationMode identifies which of the two overlapping bootstrap policy storage models a cluster is
+// currently operating in.
+type AuthorizationMode string
+
+const (
+	// AuthorizationModeLegacy means cluster policy is materialized from a single ClusterPolicy object - the
+	// storage model ReconcileComponentAuthorizationRules has always assumed.
+	AuthorizationModeLegacy AuthorizationMode = "legacy"
+	// AuthorizationModeRBAC means cluster roles already live as native rbac.ClusterRole objects, independent
+	// of any ClusterPolicy object.
+	AuthorizationModeRBAC AuthorizationMode = "rbac"
+	// AuthorizationModeAmbiguous means both a legacy ClusterPolicy object and native rbac ClusterRole objects
+	// were observed at once - expected only mid-migration - so ReconcileComponentAuthorizationRules can't
+	// safely pick a path without risking duplicated or conflicting policy.
+	AuthorizationModeAmbiguous AuthorizationMode = "ambiguous"
+)
+
+// detectAuthorizationMode classifies a cluster's authorization storage model from two independently probed
+// signals: legacyPresent (a ClusterPolicy object already exists) and rbacPresent (a native rbac.ClusterRole
+// already exists). Both present is reported as AuthorizationModeAmbiguous rather than guessing, since running
+// the legacy-oriented reconcile path against a cluster whose roles already live in native rbac storage - or
+// vice versa - can duplicate or conflict policy. Neither present is reported as AuthorizationModeLegacy,
+// matching ReconcileComponentAuthorizationRules' long-standing default behavior on a fresh cluster.
+func detectAuthorizationMode(legacyPresent, rbacPresent bool) AuthorizationMode {
+	switch {
+	case legacyPresent && rbacPresent:
+		return AuthorizationModeAmbiguous
+	case rbacPresent:
+		return AuthorizationModeRBAC
+	default:
+		return AuthorizationModeLegacy
+	}
+}
+
+// rbacClusterRolePresent reports whether a native rbac.ClusterRole already exists for the discovery role -
+// one of the earliest bootstrap roles created - as a proxy for "this cluster's cluster roles live in native
+// rbac storage". A nil clusterRoles means the caller left RBACClusterRoles unset, in which case the rbac
+// signal is unavailable and false is returned without error.
+func rbacClusterRolePresent(clusterRoles rbacclient.ClusterRolesGetter) (bool, error) {
+	if clusterRoles == nil {
+		return false, nil
+	}
+	_, err := clusterRoles.ClusterRoles().Get(bootstrappolicy.DiscoveryRoleName, metav1.GetOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if kapierror.IsNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// ReconcileComponentAuthorizationRules initializes and reconciles the cluster and namespaced bootstrap
+// policy roles and rolebindings described by options, recording what it did on result. It contains no
+// MasterConfig-specific logic, so it's callable both from MasterConfig.ensureComponentAuthorizationRules at
+// master startup and from an admin command reconciling a live cluster on demand.
+func ReconcileComponentAuthorizationRules(ctx context.Context, options ComponentAuthorizationRulesOptions, result *BootstrapStepResult) {
+	recordEvent := options.EventRecorder
+	if recordEvent == nil {
+		recordEvent = func(eventtype, reason, messageFmt string, args ...interface{}) {}
+	}
+	if options.Log == nil {
+		options.Log = glogBootstrapLogger{}
+	}
+
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+	existingPolicy, clusterPolicyMissing, getErr := getClusterPolicyWithRetry(reqCtx, options.ClusterPolicyRegistry)
+	if getErr != nil {
+		options.Log.Error(getErr, "Could not determine whether cluster policy exists after retrying", "file", options.BootstrapPolicyFile)
+		result.addError(getErr)
+		return
+	}
+
+	if clusterPolicyMissing && options.OverwriteBootstrapPolicyIfMissing {
+		if !confirmClusterPolicyMissing(options, reqCtx) {
+			options.Log.Info("Cluster policy Get flapped between not-found and found on recheck; skipping bootstrap policy overwrite", "file", options.BootstrapPolicyFile)
+			clusterPolicyMissing = false
+		}
+	}
+
+	rbacPresent, rbacErr := rbacClusterRolePresent(options.RBACClusterRoles)
+	if rbacErr != nil {
+		options.Log.Error(rbacErr, "Could not determine whether native rbac cluster roles exist")
+		result.addError(rbacErr)
+		return
+	}
+	switch mode := detectAuthorizationMode(!clusterPolicyMissing, rbacPresent); mode {
+	case AuthorizationModeAmbiguous:
+		err := fmt.Errorf("cluster authorization mode is ambiguous: both a legacy ClusterPolicy object and native rbac cluster roles were found; refusing to reconcile bootstrap policy to avoid duplicating or conflicting it")
+		options.Log.Error(err, "Refusing to reconcile component authorization rules")
+		result.addError(err)
+		return
+	default:
+		options.Log.Info("Detected cluster authorization mode", "mode", mode)
+	}
+
+	if clusterPolicyMissing && options.OverwriteBootstrapPolicyIfMissing {
+		policyFile, cleanupPolicyFile, err := resolveBootstrapPolicyFile(options)
+		if err != nil {
+			options.Log.Error(err, "Unable to render embedded default bootstrap policy")
+			recordEvent(kapi.EventTypeWarning, "BootstrapPolicyCreateFailed", "Failed to render embedded default bootstrap policy: %v", err)
+		} else {
+			defer cleanupPolicyFile()
+
+			options.Log.Info("No cluster policy found, creating bootstrap policy", "file", policyFile)
+
+			if err := validateBootstrapPolicyFile(policyFile); err != nil {
+				options.Log.Error(err, "Refusing to create bootstrap policy from invalid file", "file", policyFile)
+				recordEvent(kapi.EventTypeWarning, "BootstrapPolicyCreateFailed", "Failed to create bootstrap policy from %v: %v", policyFile, err)
+			} else if err := admin.OverwriteBootstrapPolicy(options.RESTOptionsGetter, policyFile, admin.CreateBootstrapPolicyFileFullCommand, true, ioutil.Discard); err != nil {
+				options.Log.Error(err, "Error creating bootstrap policy", "file", policyFile)
+				recordEvent(kapi.EventTypeWarning, "BootstrapPolicyCreateFailed", "Failed to create bootstrap policy from %v: %v", policyFile, err)
+			} else {
+				recordEvent(kapi.EventTypeNormal, "BootstrapPolicyCreated", "Created bootstrap policy from %v", policyFile)
+			}
+		}
+	} else if clusterPolicyMissing {
+		options.Log.Info("No cluster policy found, but overwrite is disabled; leaving bootstrap policy untouched", "file", options.BootstrapPolicyFile)
+	} else {
+		reapplyBootstrapPolicyFileIfChanged(reqCtx, options, existingPolicy, recordEvent)
+	}
+
+	reconcileNamespacedRoles := options.ReconcileNamespacedRoles == nil || options.ReconcileNamespacedRoles(clusterPolicyMissing)
+	if reconcileNamespacedRoles {
+		// these are namespaced, so we can't use the cluster role/rolebinding reconcile machinery on them.
+		reconcileNamespacedBootstrapRoles(ctx, kbootstrappolicy.NamespaceRoles(), options, recordEvent, result)
+		reconcileNamespacedBootstrapRoleBindings(ctx, kbootstrappolicy.NamespaceRoleBindings(), options, recordEvent, result)
+	}
+
+	// Reconcile roles that must exist for the cluster to function
+	// Be very judicious about what is placed in this list, since it will be enforced on every server start
+	rolesToReconcile := options.RolesToReconcile
+	reconcileRoles := &policy.ReconcileClusterRolesOptions{
+		RolesToReconcile: rolesToReconcile,
+		Confirmed:        !options.DryRun,
+		Union:            true,
+		Out:              options.Out,
+		RoleClient:       options.OpenShiftClient.ClusterRoles(),
+	}
+	if err := reconcileRoles.RunReconcileClusterRoles(nil, nil); err != nil {
+		if isAuthDeniedReconcileError(err) {
+			err = options.classifyLoopbackAuthFailure(err)
+			options.Log.Error(err, "Cluster role reconcile forbidden by escalation protection; the reconciling identity may be missing permissions")
+			result.inc("rolesReconcileForbidden")
+		} else {
+			options.Log.Error(err, "Could not auto reconcile roles")
+		}
+		result.addError(err)
+	} else {
+		result.Counts["rolesReconciled"] += len(rolesToReconcile)
+	}
+
+	// Reconcile rolebindings that must exist for the cluster to function
+	// Be very judicious about what is placed in this list, since it will be enforced on every server start
+	reconcileRoleBindings := &policy.ReconcileClusterRoleBindingsOptions{
+		RolesToReconcile:  rolesToReconcile,
+		Confirmed:         !options.DryRun,
+		Union:             true,
+		Out:               options.Out,
+		RoleBindingClient: options.OpenShiftClient.ClusterRoleBindings(),
+	}
+	if err := reconcileRoleBindings.RunReconcileClusterRoleBindings(nil, nil); err != nil {
+		if isAuthDeniedReconcileError(err) {
+			err = options.classifyLoopbackAuthFailure(err)
+			options.Log.Error(err, "Cluster role binding reconcile forbidden by escalation protection; the reconciling identity may be missing permissions")
+			result.inc("roleBindingsReconcileForbidden")
+		} else {
+			options.Log.Error(err, "Could not auto reconcile role bindings")
+		}
+		result.addError(err)
+	} else {
+		result.Counts["roleBindingsReconciled"] += len(rolesToReconcile)
+	}
+}
+
+// reconcileNamespacedBootstrapRoles converts each rbac.Role in roles (keyed by namespace) to an
+// authorizationapi.Role and reconciles it via reconcileNamespacedRole. A role that fails conversion is
+// counted under "namespacedRoleConversionFailures" on result, logged via utilruntime.HandleError, and
+// skipped - reconciliation continues with the rest so a single bad role can't block the others. It's split
+// out of ReconcileComponentAuthorizationRules so the conversion-and-reconcile loop can be exercised directly
+// against an injected role source in tests.
+func reconcileNamespacedBootstrapRoles(ctx context.Context, roles map[string][]rbac.Role, options ComponentAuthorizationRulesOptions, recordEvent func(eventtype, reason, messageFmt string, args ...interface{}), result *BootstrapStepResult) {
+	for namespace, namespaceRoles := range roles {
+		if err := ctx.Err(); err != nil {
+			options.Log.Error(err, "Stopping namespaced role reconciliation")
+			return
+		}
+		for _, rbacRole := range namespaceRoles {
+			role := &authorizationapi.Role{}
+			if err := authorizationapi.Convert_rbac_Role_To_authorization_Role(&rbacRole, role, nil); err != nil {
+				utilruntime.HandleError(fmt.Errorf("unable to convert role.%s/%s in %v: %v", rbac.GroupName, rbacRole.Name, namespace, err))
+				recordEvent(kapi.EventTypeWarning, "RoleConversionFailed", "Unable to convert role %s/%s in %v: %v", rbac.GroupName, rbacRole.Name, namespace, err)
+				result.inc("namespacedRoleConversionFailures")
+				continue
+			}
+			if err := reconcileNamespacedRole(options.OpenShiftClient.Roles(namespace), role, options.DryRun, options.Out); err != nil {
+				// don't fail on failures, try to reconcile as many as you can
+				utilruntime.HandleError(fmt.Errorf("unable to reconcile role.%s/%s in %v: %v", rbac.GroupName, role.Name, namespace, err))
+				recordEvent(kapi.EventTypeWarning, "RoleReconcileFailed", "Unable to reconcile role %s/%s in %v: %v", rbac.GroupName, role.Name, namespace, err)
+				result.addError(err)
+			} else {
+				recordEvent(kapi.EventTypeNormal, "RoleReconciled", "Reconciled role %s/%s in %v", rbac.GroupName, role.Name, namespace)
+				result.inc("namespacedRolesReconciled")
+			}
+		}
+	}
+}
+
+// reconcileNamespacedBootstrapRoleBindings is the RoleBinding analogue of reconcileNamespacedBootstrapRoles:
+// it converts each rbac.RoleBinding in roleBindings (keyed by namespace) to an authorizationapi.RoleBinding
+// and reconciles it via reconcileNamespacedRoleBinding, counting a conversion failure under
+// "namespacedRoleBindingConversionFailures" on result and continuing with the rest.
+func reconcileNamespacedBootstrapRoleBindings(ctx context.Context, roleBindings map[string][]rbac.RoleBinding, options ComponentAuthorizationRulesOptions, recordEvent func(eventtype, reason, messageFmt string, args ...interface{}), result *BootstrapStepResult) {
+	for namespace, namespaceRoleBindings := range roleBindings {
+		if err := ctx.Err(); err != nil {
+			options.Log.Error(err, "Stopping namespaced rolebinding reconciliation")
+			return
+		}
+		for _, rbacRoleBinding := range namespaceRoleBindings {
+			roleBinding := &authorizationapi.RoleBinding{}
+			if err := authorizationapi.Convert_rbac_RoleBinding_To_authorization_RoleBinding(&rbacRoleBinding, roleBinding, nil); err != nil {
+				utilruntime.HandleError(fmt.Errorf("unable to convert rolebinding.%s/%s in %v: %v", rbac.GroupName, rbacRoleBinding.Name, namespace, err))
+				recordEvent(kapi.EventTypeWarning, "RoleBindingConversionFailed", "Unable to convert rolebinding %s/%s in %v: %v", rbac.GroupName, rbacRoleBinding.Name, namespace, err)
+				result.inc("namespacedRoleBindingConversionFailures")
+				continue
+			}
+			if err := reconcileNamespacedRoleBinding(options.OpenShiftClient.RoleBindings(namespace), roleBinding, true, options.DryRun, options.Out); err != nil {
+				// don't fail on failures, try to reconcile as many as you can
+				utilruntime.HandleError(fmt.Errorf("unable to reconcile rolebinding.%s/%s in %v: %v", rbac.GroupName, roleBinding.Name, namespace, err))
+				recordEvent(kapi.EventTypeWarning, "RoleBindingReconcileFailed", "Unable to reconcile rolebinding %s/%s in %v: %v", rbac.GroupName, roleBinding.Name, namespace, err)
+				result.addError(err)
+			} else {
+				recordEvent(kapi.EventTypeNormal, "RoleReconciled", "Reconciled rolebinding %s/%s in %v", rbac.GroupName, roleBinding.Name, namespace)
+				result.inc("namespacedRoleBindingsReconciled")
+			}
+		}
+	}
+}
+
+// ensureComponentAuthorizationRules is a thin adapter over ReconcileComponentAuthorizationRules, supplying
+// this MasterConfig's registry, clients and PolicyConfig as the reconciliation options. See
+// ReconcileComponentAuthorizationRules for the actual logic.
+func (c *MasterConfig) ensureComponentAuthorizationRules(ctx context.Context, result *BootstrapStepResult) {
+	clusterPolicyRegistry, err := c.clusterPolicyRegistryLazy()
+	if err != nil {
+		c.bootstrapLog().Error(err, "Error creating policy storage")
+		result.addError(err)
+		return
+	}
+
+	ReconcileComponentAuthorizationRules(ctx, ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry:             clusterPolicyRegistry,
+		RESTOptionsGetter:                 c.RESTOptionsGetter,
+		OpenShiftClient:                   c.PrivilegedLoopbackOpenShiftClient,
+		BootstrapPolicyFile:               c.Options.PolicyConfig.BootstrapPolicyFile,
+		OpenShiftSharedResourcesNamespace: c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace,
+		OverwriteBootstrapPolicyIfMissing: c.overwriteBootstrapPolicyIfMissing(),
+		ClusterPolicyMissingRecheckDelay:  time.Duration(c.Options.PolicyConfig.Bootstrap.ClusterPolicyMissingRecheckDelaySeconds) * time.Second,
+		ReconcileNamespacedRoles:          c.reconcileNamespacedBootstrapRoles,
+		RolesToReconcile:                  c.clusterRolesToReconcile(),
+		DryRun:                            c.DryRunBootstrap,
+		Out:                               c.bootstrapOutput(),
+		EventRecorder:                     c.recordBootstrapEvent,
+		Log:                               c.bootstrapLog(),
+		ClassifyLoopbackAuthFailure:       c.classifyLoopbackAuthFailure,
+	}, result)
+
+	if c.Options.PolicyConfig.Bootstrap.VerifyBootstrapRoleBindingIntegrity {
+		c.verifyBootstrapRoleBindingIntegrity(result)
+	}
+}
+
+// OrphanedBootstrapRoleBinding names a bootstrap-owned cluster role binding whose RoleRef no longer resolves
+// to an existing cluster role - typically left behind when a cluster role is renamed or removed across an
+// upgrade, and a subtle source of authz gaps since the binding grants access to a role that's no longer there.
+type OrphanedBootstrapRoleBinding struct {
+	Name           string
+	MissingRoleRef string
+}
+
+// DetectOrphanedBootstrapRoleBindings lists every bootstrap-owned cluster role binding
+// (bootstrappolicy.GetBootstrapClusterRoleBindings) and checks that its RoleRef still resolves to an existing
+// cluster role, returning the ones that don't. It's read-only and always available regardless of
+// PolicyConfig.Bootstrap.VerifyBootstrapRoleBindingIntegrity or PruneOrphanedBootstrapRoleBindings - those
+// only control whether EnsureBootstrapPolicy calls it and whether it deletes what it finds - so detection can
+// be wired into monitoring independent of bootstrap.
+func (c *MasterConfig) DetectOrphanedBootstrapRoleBindings() ([]OrphanedBootstrapRoleBinding, error) {
+	roleClient := c.PrivilegedLoopbackOpenShiftClient.ClusterRoles()
+
+	missing := sets.NewString()
+	present := sets.NewString()
+	orphans := []OrphanedBootstrapRoleBinding{}
+	for _, binding := range bootstrappolicy.GetBootstrapClusterRoleBindings() {
+		roleName := binding.RoleRef.Name
+		if !missing.Has(roleName) && !present.Has(roleName) {
+			_, err := roleClient.Get(roleName, metav1.GetOptions{})
+			switch {
+			case kapierror.IsNotFound(err):
+				missing.Insert(roleName)
+			case err != nil:
+				return nil, err
+			default:
+				present.Insert(roleName)
+			}
+		}
+		if missing.Has(roleName) {
+			orphans = append(orphans, OrphanedBootstrapRoleBinding{Name: binding.Name, MissingRoleRef: roleName})
+		}
+	}
+	return orphans, nil
+}
+
+// verifyBootstrapRoleBindingIntegrity runs DetectOrphanedBootstrapRoleBindings and logs any orphan found,
+// deleting it first when PruneOrphanedBootstrapRoleBindings is set. Called from ensureComponentAuthorizationRules
+// only when PolicyConfig.Bootstrap.VerifyBootstrapRoleBindingIntegrity is true, since walking every bootstrap
+// role binding's RoleRef costs an extra Get per distinct role on every bootstrap run.
+func (c *MasterConfig) verifyBootstrapRoleBindingIntegrity(result *BootstrapStepResult) {
+	orphans, err := c.DetectOrphanedBootstrapRoleBindings()
+	if err != nil {
+		c.bootstrapLog().Error(err, "Unable to detect orphaned bootstrap role bindings")
+		result.addError(err)
+		return
+	}
+
+	prune := c.Options.PolicyConfig.Bootstrap.PruneOrphanedBootstrapRoleBindings
+	bindingClient := c.PrivilegedLoopbackOpenShiftClient.ClusterRoleBindings()
+	for _, orphan := range orphans {
+		c.bootstrapLog().Info("Detected orphaned bootstrap role binding referencing a missing cluster role", "rolebinding", orphan.Name, "role", orphan.MissingRoleRef)
+		result.inc("orphanedRoleBindingsDetected")
+		if !prune {
+			continue
+		}
+		if err := bindingClient.Delete(orphan.Name); err != nil && !kapierror.IsNotFound(err) {
+			c.bootstrapLog().Error(err, "Unable to prune orphaned bootstrap role binding", "rolebinding", orphan.Name)
+			result.addError(err)
+			continue
+		}
+		c.bootstrapLog().Info("Pruned orphaned bootstrap role binding", "rolebinding", orphan.Name)
+		result.inc("orphanedRoleBindingsPruned")
+	}
+}
+
+// bootstrapClusterRoleNames returns the names of every cluster role in bootstrappolicy.GetBootstrapClusterRoles
+// - which already unions ControllerRoles, the discovery role, and the rest of the static bootstrap policy -
+// as a set, so callers can validate a configured role name against it without re-walking the role list.
+func bootstrapClusterRoleNames() sets.String {
+	names := sets.NewString()
+	for _, role := range bootstrappolicy.GetBootstrapClusterRoles() {
+		names.Insert(role.Name)
+	}
+	return names
+}
+
+// ExpectedBootstrapClusterRoleNames returns the names of every cluster role bootstrap creates or reconciles.
+// It's a read-only composition of bootstrappolicy.GetBootstrapClusterRoles - which already unions
+// ControllerRoles, the discovery role, and the rest of the static bootstrap policy - giving operators and
+// conformance tests a single source of truth to diff against `oc get clusterroles`, instead of re-deriving
+// the expected set themselves.
+func ExpectedBootstrapClusterRoleNames() []string {
+	return bootstrapClusterRoleNames().List()
+}
+
+// clusterRolesToReconcile returns the built-in discovery role plus any configured
+// PolicyConfig.AlwaysReconcileClusterRoles entries that name a known bootstrap cluster role. Entries that
+// don't match a known role are logged and dropped rather than handed to the reconcile options, since an
+// unrecognized name there would just be a silent no-op.
+func (c *MasterConfig) clusterRolesToReconcile() []string {
+	knownClusterRoleNames := bootstrapClusterRoleNames()
+
+	rolesToReconcile := []string{bootstrappolicy.DiscoveryRoleName}
+	for _, name := range c.Options.PolicyConfig.AlwaysReconcileClusterRoles {
+		if !knownClusterRoleNames.Has(name) {
+			c.bootstrapLog().Info("Ignoring unknown cluster role in AlwaysReconcileClusterRoles", "role", name)
+			continue
+		}
+		rolesToReconcile = append(rolesToReconcile, name)
+	}
+	return rolesToReconcile
+}
+
+// ErrBootstrapAlreadyRunning is returned by EnsureBootstrapPolicy when another call is already reconciling
+// bootstrap policy on the same MasterConfig. EnsureBootstrapPolicy fails fast rather than blocking the second
+// caller, since bootstrap runs during master startup where waiting on a concurrent, possibly-stuck run is
+// worse than surfacing the conflict immediately - a restart-during-startup race is exactly the scenario this
+// guards against.
+var ErrBootstrapAlreadyRunning = errors.New("bootstrap policy is already being reconciled")
+
+// EnsureBootstrapPolicy guards against concurrent invocation: a call that arrives while another is already in
+// progress returns ErrBootstrapAlreadyRunning immediately instead of double-applying the ensure* steps or
+// racing with them. See ErrBootstrapAlreadyRunning.
+//
+// It first validates the PolicyConfig fields the steps below depend on via
+// validateBootstrapNamespaceConfig, returning immediately with a descriptive error - before any step runs -
+// rather than letting a missing or malformed namespace name surface later as a cryptic apiserver validation
+// failure deep inside an ensure* method.
+//
+// It then runs the bootstrap policy initialization steps in the order their dependencies
+// require: cluster and namespaced roles/rolebindings must exist before the infra namespace tries to bind
+// them to its service accounts, the infra namespace must exist before the shared-resources namespace's
+// service account role bindings are created, and the default namespace and default SCCs only need the
+// cluster roles to already be in place.
+//
+// Before each step runs, EnsureBootstrapPolicy checks whether it's named in BootstrapOptions.DisabledSteps
+// (see skipDisabledBootstrapStep); a disabled step is skipped entirely, recording "skipped" on its
+// BootstrapStepResult instead of running.
+//
+// Each step's failure is handled according to its resolved BootstrapOptions.StepFailurePolicy (see
+// enforceBootstrapStepFailurePolicy): a Required step's failure short-circuits EnsureBootstrapPolicy, which
+// returns that error immediately, while an Optional step's failure is only recorded on its BootstrapStepResult
+// and EnsureBootstrapPolicy continues on to the next step. By default shared_resources_namespace and scc are
+// Required - the shared-resources namespace's templates and imagestreams and the default SCCs are relied on
+// elsewhere - and the rest are Optional, since each already logs its own failures via glog and is safe to
+// retry on the next master restart; StepFailurePolicies can override either direction. ctx is threaded into
+// each step and honored by their retry/backoff and polling loops, so a shutting-down master can cancel out
+// of bootstrap instead of blocking it; EnsureBootstrapPolicy also checks ctx between steps so it doesn't
+// start a new one after cancellation.
+//
+// The returned BootstrapResult records what each step actually did - counts of objects created, updated,
+// skipped or reconciled, plus any errors encountered - so callers like an operator can report bootstrap
+// completeness without scraping log lines. It is always non-nil, even when EnsureBootstrapPolicy returns an
+// error, and reflects whatever steps managed to run before the error occurred.
+//
+// Before checking bootstrapUpToDate or running any step, EnsureBootstrapPolicy calls waitForAPIServerReady,
+// which polls a cheap read against the loopback client until it succeeds or PolicyConfig.Bootstrap.
+// ReadinessTimeoutSeconds elapses. During combined startup the loopback client can reach EnsureBootstrapPolicy
+// before the apiserver has finished initializing; probing first turns that race into a bounded wait instead of
+// a burst of spurious errors from the first few steps.
+//
+// Before running any step, EnsureBootstrapPolicy checks bootstrapUpToDate: on a steady-state restart where
+// the infra namespace already records this exact binary version as having completed bootstrap, every
+// ensure* step is skipped entirely rather than re-issuing the GETs each would otherwise spend confirming
+// nothing changed. MasterConfig.ForceFullBootstrap bypasses this fast path unconditionally.
+//
+// Independently of that fast path, each step that finishes with BootstrapStepOutcomeSuccess, while no earlier
+// step of the same run has failed, is also recorded individually via recordBootstrapStepComplete. If a restart lands mid-bootstrap - say component_authz failed
+// and the process was killed before reaching scc - the next EnsureBootstrapPolicy run skips every step up to
+// and including the last one recorded complete and resumes at the step that never finished, via
+// skipAlreadyCompletedBootstrapStep. As with bootstrapUpToDate, MasterConfig.ForceFullBootstrap bypasses this
+// unconditionally.
+//
+// Once the required steps have succeeded - including on the bootstrapUpToDate fast path - EnsureBootstrapPolicy
+// runs any hooks registered via RegisterPostBootstrapHook, in registration order. This is the supported
+// extension point for one-time initialization that depends on bootstrap having finished, so callers don't
+// need to patch this function directly. Hooks are skipped entirely if any Required step failed, since
+// EnsureBootstrapPolicy already returns before reaching them in that case.
+//
+// Immediately before running any step, EnsureBootstrapPolicy calls dumpBootstrapPolicyPlan, which at V(4)
+// logs the complete computed plan - every cluster role, cluster role binding, security context constraint,
+// and namespace bootstrap intends to ensure - for debugging why a particular permission is or isn't applied.
+//
+// Each step also reports its outcome to MasterConfig.BootstrapStepCallback, if set - once per step, whether
+// it succeeded, failed, or was skipped - so a caller like a cluster operator can translate outcomes into
+// status conditions synchronously, in process, without scraping logs or polling the returned BootstrapResult.
+//
+// Whichever way EnsureBootstrapPolicy finishes - the bootstrapUpToDate fast path, a completed run, or a
+// Required step's failure - it also emits exactly one consolidated summary log line via logBootstrapOutcome,
+// so an operator tailing logs at default verbosity can tell bootstrap succeeded or find the one step that
+// failed without having to raise -v to see the individual ensure* steps' own logging.
+func (c *MasterConfig) EnsureBootstrapPolicy(ctx context.Context) (*BootstrapResult, error) {
+	if !atomic.CompareAndSwapInt32(&c.bootstrapRunning, 0, 1) {
+		return newBootstrapResult(), ErrBootstrapAlreadyRunning
+	}
+	defer atomic.StoreInt32(&c.bootstrapRunning, 0)
+
+	RegisterBootstrapMetrics()
+	result := newBootstrapResult()
+
+	if err := c.validateBootstrapNamespaceConfig(); err != nil {
+		c.bootstrapLog().Error(err, "Invalid bootstrap policy configuration")
+		return result, err
+	}
+
+	if err := c.waitForAPIServerReady(ctx); err != nil {
+		c.bootstrapLog().Error(err, "Apiserver did not become ready in time; aborting bootstrap")
+		return result, err
+	}
+
+	if c.bootstrapUpToDate() {
+		c.bootstrapLog().Info("Bootstrap policy already up to date for this master version; skipping reconcile", "version", version.Get().String())
+		result.step("version_precheck").inc("skipped")
+		c.setBootstrapComplete()
+		c.runPostBootstrapHooks(ctx, result)
+		c.logBootstrapOutcome(result, "", nil)
+		return result, nil
+	}
+
+	c.dumpBootstrapPolicyPlan()
+
+	// earlierStepFailed stops recordBootstrapStepComplete once any step of this run has failed, so a restart
+	// resumes at the failed step rather than past it.
+	earlierStepFailed := false
+
+	if !c.skipDisabledBootstrapStep(configapi.BootstrapStepComponentAuthz, result) && !c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepComponentAuthz, result) {
+		if err := observeBootstrapStep(configapi.BootstrapStepComponentAuthz, func() error {
+			return c.traceBootstrapStep(configapi.BootstrapStepComponentAuthz, nil, func() error {
+				stepResult := result.step(configapi.BootstrapStepComponentAuthz)
+				stepErr := c.runBootstrapStepWithTimeout(ctx, stepResult, func(stepCtx context.Context) error {
+					c.ensureComponentAuthorizationRules(stepCtx, stepResult)
+					return nil
+				})
+				outcome, outcomeErr := bootstrapStepOutcome(stepErr, stepResult)
+				if outcome == BootstrapStepOutcomeSuccess && !earlierStepFailed {
+					c.recordBootstrapStepComplete(configapi.BootstrapStepComponentAuthz)
+				} else if outcome != BootstrapStepOutcomeSuccess {
+					earlierStepFailed = true
+				}
+				c.reportBootstrapStep(configapi.BootstrapStepComponentAuthz, outcome, outcomeErr)
+				return c.enforceBootstrapStepFailurePolicy(configapi.BootstrapStepComponentAuthz, stepResult, stepErr)
+			})
+		}); err != nil {
+			c.logBootstrapOutcome(result, configapi.BootstrapStepComponentAuthz, err)
+			return result, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	if !c.skipDisabledBootstrapStep(configapi.BootstrapStepInfraNamespace, result) && !c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepInfraNamespace, result) {
+		if err := observeBootstrapStep(configapi.BootstrapStepInfraNamespace, func() error {
+			return c.traceBootstrapStep(configapi.BootstrapStepInfraNamespace, map[string]interface{}{"namespace": c.infraNamespace()}, func() error {
+				stepResult := result.step(configapi.BootstrapStepInfraNamespace)
+				stepErr := c.runBootstrapStepWithTimeout(ctx, stepResult, func(stepCtx context.Context) error {
+					if _, err := c.ensureOpenShiftInfraNamespace(stepCtx, stepResult); err != nil {
+						c.bootstrapLog().Error(err, "Controller role reconciliation encountered failures")
+					}
+					return nil
+				})
+				outcome, outcomeErr := bootstrapStepOutcome(stepErr, stepResult)
+				if outcome == BootstrapStepOutcomeSuccess && !earlierStepFailed {
+					c.recordBootstrapStepComplete(configapi.BootstrapStepInfraNamespace)
+				} else if outcome != BootstrapStepOutcomeSuccess {
+					earlierStepFailed = true
+				}
+				c.reportBootstrapStep(configapi.BootstrapStepInfraNamespace, outcome, outcomeErr)
+				return c.enforceBootstrapStepFailurePolicy(configapi.BootstrapStepInfraNamespace, stepResult, stepErr)
+			})
+		}); err != nil {
+			c.logBootstrapOutcome(result, configapi.BootstrapStepInfraNamespace, err)
+			return result, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	if !c.skipDisabledBootstrapStep(configapi.BootstrapStepSharedResourcesNamespace, result) && !c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepSharedResourcesNamespace, result) {
+		if err := observeBootstrapStep(configapi.BootstrapStepSharedResourcesNamespace, func() error {
+			tags := map[string]interface{}{"namespace": c.Options.PolicyConfig.OpenShiftSharedResourcesNamespace}
+			return c.traceBootstrapStep(configapi.BootstrapStepSharedResourcesNamespace, tags, func() error {
+				stepResult := result.step(configapi.BootstrapStepSharedResourcesNamespace)
+				stepErr := c.runBootstrapStepWithTimeout(ctx, stepResult, func(stepCtx context.Context) error {
+					_, err := c.EnsureOpenShiftSharedResourcesNamespace(stepCtx, stepResult)
+					return err
+				})
+				outcome, outcomeErr := bootstrapStepOutcome(stepErr, stepResult)
+				if outcome == BootstrapStepOutcomeSuccess && !earlierStepFailed {
+					c.recordBootstrapStepComplete(configapi.BootstrapStepSharedResourcesNamespace)
+				} else if outcome != BootstrapStepOutcomeSuccess {
+					earlierStepFailed = true
+				}
+				c.reportBootstrapStep(configapi.BootstrapStepSharedResourcesNamespace, outcome, outcomeErr)
+				return c.enforceBootstrapStepFailurePolicy(configapi.BootstrapStepSharedResourcesNamespace, stepResult, stepErr)
+			})
+		}); err != nil {
+			c.logBootstrapOutcome(result, configapi.BootstrapStepSharedResourcesNamespace, err)
+			return result, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	if !c.skipDisabledBootstrapStep(configapi.BootstrapStepDefaultNamespaceSARoles, result) && !c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepDefaultNamespaceSARoles, result) {
+		if err := observeBootstrapStep(configapi.BootstrapStepDefaultNamespaceSARoles, func() error {
+			tags := map[string]interface{}{"namespace": metav1.NamespaceDefault}
+			return c.traceBootstrapStep(configapi.BootstrapStepDefaultNamespaceSARoles, tags, func() error {
+				stepResult := result.step(configapi.BootstrapStepDefaultNamespaceSARoles)
+				stepErr := c.runBootstrapStepWithTimeout(ctx, stepResult, func(stepCtx context.Context) error {
+					c.ensureDefaultNamespaceServiceAccountRoles(stepCtx, stepResult)
+					return nil
+				})
+				outcome, outcomeErr := bootstrapStepOutcome(stepErr, stepResult)
+				if outcome == BootstrapStepOutcomeSuccess && !earlierStepFailed {
+					c.recordBootstrapStepComplete(configapi.BootstrapStepDefaultNamespaceSARoles)
+				} else if outcome != BootstrapStepOutcomeSuccess {
+					earlierStepFailed = true
+				}
+				c.reportBootstrapStep(configapi.BootstrapStepDefaultNamespaceSARoles, outcome, outcomeErr)
+				return c.enforceBootstrapStepFailurePolicy(configapi.BootstrapStepDefaultNamespaceSARoles, stepResult, stepErr)
+			})
+		}); err != nil {
+			c.logBootstrapOutcome(result, configapi.BootstrapStepDefaultNamespaceSARoles, err)
+			return result, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	if !c.skipDisabledBootstrapStep(configapi.BootstrapStepSCC, result) && !c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepSCC, result) {
+		if err := observeBootstrapStep(configapi.BootstrapStepSCC, func() error {
+			return c.traceBootstrapStep(configapi.BootstrapStepSCC, nil, func() error {
+				stepResult := result.step(configapi.BootstrapStepSCC)
+				stepErr := c.runBootstrapStepWithTimeout(ctx, stepResult, func(stepCtx context.Context) error {
+					created, err := c.ensureDefaultSecurityContextConstraints(stepCtx, stepResult)
+					if err == nil && created == 0 && len(stepResult.Names["existing"]) == 0 {
+						c.bootstrapLog().Info("Bootstrap created no security context constraints and found none already present; this likely indicates an empty bootstrap SCC list")
+					}
+					return err
+				})
+				outcome, outcomeErr := bootstrapStepOutcome(stepErr, stepResult)
+				if outcome == BootstrapStepOutcomeSuccess && !earlierStepFailed {
+					c.recordBootstrapStepComplete(configapi.BootstrapStepSCC)
+				}
+				c.reportBootstrapStep(configapi.BootstrapStepSCC, outcome, outcomeErr)
+				return c.enforceBootstrapStepFailurePolicy(configapi.BootstrapStepSCC, stepResult, stepErr)
+			})
+		}); err != nil {
+			c.logBootstrapOutcome(result, configapi.BootstrapStepSCC, err)
+			return result, err
+		}
+	}
+
+	c.recordBootstrapVersionComplete(ctx)
+	c.setBootstrapComplete()
+	c.runPostBootstrapHooks(ctx, result)
+	c.logBootstrapOutcome(result, "", nil)
+	return result, nil
+}
+
+// bootstrapResultTotals sums result's per-step Counts into the categories logBootstrapOutcome's summary line
+// reports. Counts category names aren't standardized across steps - the scc step alone uses "reconciled" for
+// what other steps might call "updated" - so this tolerates either spelling, and a step that never populates
+// a given category simply contributes zero rather than requiring every ensure* step to agree on category
+// names.
+func bootstrapResultTotals(result *BootstrapResult) (steps, created, updated, skipped int) {
+	steps = len(result.Steps)
+	for _, stepResult := range result.Steps {
+		created += stepResult.Counts["created"]
+		updated += stepResult.Counts["reconciled"] + stepResult.Counts["updated"]
+		skipped += stepResult.Counts["skipped"]
+	}
+	return steps, created, updated, skipped
+}
+
+// logBootstrapOutcome emits the single consolidated log line EnsureBootstrapPolicy produces on its
+// bootstrapUpToDate fast path, after a completed run, and when a step's failure aborts the run. Its other
+// returns - ErrBootstrapAlreadyRunning, an invalid PolicyConfig, an apiserver that never became ready, and ctx
+// cancelled between steps - emit no summary line. failedStep is the name of the step that failed
+// - see bootstrapStepOrder for the possible values - and failureErr its error; both are empty/nil on success,
+// in which case the line instead summarizes result via bootstrapResultTotals. This is deliberately the one
+// line meant to be visible at default verbosity: the individual ensure* steps already log their own details,
+// but an operator scanning startup logs shouldn't have to reconstruct whether bootstrap succeeded from those.
+func (c *MasterConfig) logBootstrapOutcome(result *BootstrapResult, failedStep string, failureErr error) {
+	if failureErr != nil {
+		c.bootstrapLog().Info(fmt.Sprintf("Bootstrap policy initialization failed at step %s: %v", failedStep, failureErr))
+		return
+	}
+	steps, created, updated, skipped := bootstrapResultTotals(result)
+	c.bootstrapLog().Info(fmt.Sprintf("Bootstrap policy initialization completed: %d steps, %d created, %d updated, %d skipped", steps, created, updated, skipped))
+}
+
+// dumpBootstrapPolicyPlan logs, at V(4), the complete computed bootstrap plan - every cluster role, cluster
+// role binding, security context constraint, and namespace EnsureBootstrapPolicy is about to ensure - before
+// any step runs. It's built from the exact same sources the ensure* steps themselves compute from
+// (bootstrappolicy.GetBootstrapClusterRoles, GetBootstrapClusterRoleBindings, and
+// GetBootstrapSecurityContextConstraints with SecurityContextConstraintPriorityOverrides already applied), so
+// the dump can never drift from what bootstrap actually does. It never talks to the API server and has no
+// effect on bootstrap itself, so it's safe to call unconditionally; the V(4) guard just avoids the wasted
+// work of assembling the plan when nobody's going to read it.
+func (c *MasterConfig) dumpBootstrapPolicyPlan() {
+	if !glog.V(4) {
+		return
+	}
+
+	clusterRoleNames := sets.NewString()
+	for _, role := range bootstrappolicy.GetBootstrapClusterRoles() {
+		clusterRoleNames.Insert(role.Name)
+	}
+
+	roleBindingNames := sets.NewString()
+	for _, binding := range bootstrappolicy.GetBootstrapClusterRoleBindings() {
+		roleBindingNames.Insert(binding.Name)
+	}
+
+	ns := c.infraNamespace()
+	bootstrapSCCGroups, bootstrapSCCUsers := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(bootstrapSCCGroups, bootstrapSCCUsers)
+	if err := applySCCPriorityOverrides(bootstrapSCCs, c.Options.PolicyConfig.SecurityContextConstraintPriorityOverrides); err != nil {
+		// an invalid override is reported again, more usefully, when ensureDefaultSecurityContextConstraints
+		// actually runs; the dump just falls back to the un-overridden priorities rather than failing bootstrap.
+		glog.V(4).Infof("Bootstrap plan dump: could not apply security context constraint priority overrides: %v", err)
+	}
+	sccNames := sets.NewString()
+	for _, scc := range bootstrapSCCs {
+		sccNames.Insert(scc.Name)
+	}
+
+	namespaces := append([]string{ns}, c.sharedResourcesNamespaces()...)
+
+	glog.V(4).Infof("Computed bootstrap plan: namespaces=%v clusterRoles=%v clusterRoleBindings=%v securityContextConstraints=%v",
+		namespaces, clusterRoleNames.List(), roleBindingNames.List(), sccNames.List())
+}
+
+// bootstrapConfigDump is the serializable snapshot DumpBootstrapConfig writes out. Field names are chosen to
+// read naturally as YAML keys in a bug report rather than to mirror Go naming.
+type bootstrapConfigDump struct {
+	Options                    configapi.BootstrapOptions `json:"options"`
+	Namespaces                 []string                   `json:"namespaces"`
+	ClusterRoles               []string                   `json:"clusterRoles"`
+	ClusterRoleBindings        []string                   `json:"clusterRoleBindings"`
+	SecurityContextConstraints []string                   `json:"securityContextConstraints"`
+	BootstrapPolicyFile        string                     `json:"bootstrapPolicyFile"`
+}
+
+// DumpBootstrapConfig serializes the effective BootstrapOptions, the resolved namespace names, and the
+// cluster roles, cluster role bindings, and security context constraints EnsureBootstrapPolicy would ensure,
+// as a single YAML document written to w. It composes the same getters dumpBootstrapPolicyPlan logs at V(4),
+// so it's read-only and safe to call at any time - including against a running master - to capture exactly
+// what bootstrap is configured to do for a support case, without having to scrape log lines.
+func (c *MasterConfig) DumpBootstrapConfig(w io.Writer) error {
+	ns := c.infraNamespace()
+
+	clusterRoleNames := sets.NewString()
+	for _, role := range bootstrappolicy.GetBootstrapClusterRoles() {
+		clusterRoleNames.Insert(role.Name)
+	}
+
+	roleBindingNames := sets.NewString()
+	for _, binding := range bootstrappolicy.GetBootstrapClusterRoleBindings() {
+		roleBindingNames.Insert(binding.Name)
+	}
+
+	bootstrapSCCGroups, bootstrapSCCUsers := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(bootstrapSCCGroups, bootstrapSCCUsers)
+	if err := applySCCPriorityOverrides(bootstrapSCCs, c.Options.PolicyConfig.SecurityContextConstraintPriorityOverrides); err != nil {
+		// an invalid override is reported again, more usefully, when ensureDefaultSecurityContextConstraints
+		// actually runs; the dump just falls back to the un-overridden priorities rather than failing.
+		glog.V(4).Infof("Bootstrap config dump: could not apply security context constraint priority overrides: %v", err)
+	}
+	sccNames := sets.NewString()
+	for _, scc := range bootstrapSCCs {
+		sccNames.Insert(scc.Name)
+	}
+
+	dump := bootstrapConfigDump{
+		Options:                    c.Options.PolicyConfig.Bootstrap,
+		Namespaces:                 append([]string{ns}, c.sharedResourcesNamespaces()...),
+		ClusterRoles:               clusterRoleNames.List(),
+		ClusterRoleBindings:        roleBindingNames.List(),
+		SecurityContextConstraints: sccNames.List(),
+		BootstrapPolicyFile:        c.Options.PolicyConfig.BootstrapPolicyFile,
+	}
+
+	content, err := yaml.Marshal(dump)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// bootstrapUpToDate reports whether the infra namespace's bootstrap-master-version annotation already
+// matches this binary's version, meaning a prior EnsureBootstrapPolicy run already reconciled everything at
+// this exact version and the heavyweight ensure* steps can be skipped on this restart. Returns false - never
+// skip - on any error reading the namespace, including NotFound, and whenever ForceFullBootstrap is set, so
+// a missing or unreadable sentinel always falls through to a full run.
+func (c *MasterConfig) bootstrapUpToDate() bool {
+	if c.ForceFullBootstrap {
+		return false
+	}
+	ns := c.infraNamespace()
+	namespace, err := c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return namespace.Annotations[bootstrapMasterVersionAnnotation] == version.Get().String()
+}
+
+// recordBootstrapVersionComplete stamps the infra namespace with the version of the binary that just
+// finished a full EnsureBootstrapPolicy pass, so the next master restart's bootstrapUpToDate fast path can
+// recognize there's nothing left to reconcile. It retries on conflict like the other annotation writers in
+// this file, and logs and swallows any other error: failing to record completion only costs the next restart
+// a full (harmless) reconcile pass rather than leaving anything mis-provisioned.
+func (c *MasterConfig) recordBootstrapVersionComplete(ctx context.Context) {
+	ns := c.infraNamespace()
+	currentVersion := version.Get().String()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		namespace, err := c.bootstrapKubeClient().Core().Namespaces().Get(ns, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if namespace.Annotations[bootstrapMasterVersionAnnotation] == currentVersion {
+			return nil
+		}
+		if namespace.Annotations == nil {
+			namespace.Annotations = map[string]string{}
+		}
+		namespace.Annotations[bootstrapMasterVersionAnnotation] = currentVersion
+		_, err = c.bootstrapKubeClient().Core().Namespaces().Update(namespace)
+		return err
+	})
+	if err != nil {
+		c.bootstrapLog().Error(err, "Error recording completed bootstrap version on infra namespace", "namespace", ns)
+		return
 	}
+	c.auditBootstrapMutation("namespace-annotate", ns)
 }
@@ -0,0 +1,5665 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/discovery"
+	restclient "k8s.io/client-go/rest"
+	clientgotesting "k8s.io/client-go/testing"
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/rbac"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/apis/authorization"
+	osclient "github.com/openshift/origin/pkg/client"
+	configapi "github.com/openshift/origin/pkg/cmd/server/api"
+	"github.com/openshift/origin/pkg/cmd/server/bootstrappolicy"
+	securityapi "github.com/openshift/origin/pkg/security/apis/security"
+	securityapiv1 "github.com/openshift/origin/pkg/security/apis/security/v1"
+	"github.com/openshift/origin/pkg/version"
+)
+
+// fakeSCCClient is a minimal in-memory legacyclient.SecurityContextConstraintInterface for exercising
+// reconcileSecurityContextConstraint without a real apiserver.
+type fakeSCCClient struct {
+	sccs    map[string]*securityapi.SecurityContextConstraints
+	updated *securityapi.SecurityContextConstraints
+	deleted []string
+}
+
+func newFakeSCCClient(existing *securityapi.SecurityContextConstraints) *fakeSCCClient {
+	return &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{existing.Name: existing}}
+}
+
+func (f *fakeSCCClient) List(opts metav1.ListOptions) (*securityapi.SecurityContextConstraintsList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	list := &securityapi.SecurityContextConstraintsList{}
+	for _, scc := range f.sccs {
+		if selector.Matches(labels.Set(scc.Labels)) {
+			list.Items = append(list.Items, *scc)
+		}
+	}
+	return list, nil
+}
+func (f *fakeSCCClient) Get(name string, options metav1.GetOptions) (*securityapi.SecurityContextConstraints, error) {
+	scc, ok := f.sccs[name]
+	if !ok {
+		return nil, kapierror.NewNotFound(kapi.Resource("securitycontextconstraints"), name)
+	}
+	return scc, nil
+}
+func (f *fakeSCCClient) Create(scc *securityapi.SecurityContextConstraints) (*securityapi.SecurityContextConstraints, error) {
+	if _, exists := f.sccs[scc.Name]; exists {
+		return nil, kapierror.NewAlreadyExists(kapi.Resource("securitycontextconstraints"), scc.Name)
+	}
+	f.sccs[scc.Name] = scc
+	return scc, nil
+}
+func (f *fakeSCCClient) Update(scc *securityapi.SecurityContextConstraints) (*securityapi.SecurityContextConstraints, error) {
+	f.sccs[scc.Name] = scc
+	f.updated = scc
+	return scc, nil
+}
+func (f *fakeSCCClient) Delete(name string) error {
+	delete(f.sccs, name)
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+func (f *fakeSCCClient) Watch(opts metav1.ListOptions) (watch.Interface, error) { return nil, nil }
+
+func TestEnsureOpenShiftSharedResourcesNamespaceNonNotFoundError(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, kapierror.NewInternalError(errors.New("etcd timeout"))
+	})
+
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+			},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+	}
+
+	_, err := c.EnsureOpenShiftSharedResourcesNamespace(context.Background(), &BootstrapStepResult{Counts: map[string]int{}})
+	if err == nil {
+		t.Fatalf("expected an error to be reported for a non-NotFound Get failure, got nil")
+	}
+
+	for _, action := range fakeKubeClient.Actions() {
+		if action.GetVerb() == "create" {
+			t.Fatalf("did not expect a Create when Get failed with a non-NotFound error, got action: %v", action)
+		}
+	}
+}
+
+// TestEnsureOpenShiftSharedResourcesNamespaceReportsCreatedWhenNamespaceIsNew verifies that
+// EnsureOpenShiftSharedResourcesNamespace returns created=true when the shared resources namespace didn't
+// exist yet and this call created it.
+func TestEnsureOpenShiftSharedResourcesNamespaceReportsCreatedWhenNamespaceIsNew(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+			},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+	}
+
+	created, err := c.EnsureOpenShiftSharedResourcesNamespace(context.Background(), &BootstrapStepResult{Counts: map[string]int{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Error("expected created to be true for a freshly created shared resources namespace")
+	}
+}
+
+// TestEnsureOpenShiftSharedResourcesNamespaceReportsNotCreatedWhenNamespaceAlreadyExists verifies that
+// EnsureOpenShiftSharedResourcesNamespace returns created=false when the shared resources namespace already
+// existed.
+func TestEnsureOpenShiftSharedResourcesNamespaceReportsNotCreatedWhenNamespaceAlreadyExists(t *testing.T) {
+	existing := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift"}}
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+			},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(existing),
+	}
+
+	created, err := c.EnsureOpenShiftSharedResourcesNamespace(context.Background(), &BootstrapStepResult{Counts: map[string]int{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Error("expected created to be false for a shared resources namespace that already existed")
+	}
+}
+
+// fakeNamespaceObjectCreator is a minimal in-memory NamespaceObjectCreator, recording every object it was
+// asked to create keyed by namespace/kind/name and treating a repeated key as an already-exists no-op, the
+// same way the real dynamic-client-backed implementation does.
+type fakeNamespaceObjectCreator struct {
+	created map[string]runtime.Object
+}
+
+func (f *fakeNamespaceObjectCreator) Create(ns string, gvk schema.GroupVersionKind, obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := ns + "/" + gvk.Kind + "/" + accessor.GetName()
+	if _, exists := f.created[key]; exists {
+		return nil
+	}
+	f.created[key] = obj
+	return nil
+}
+
+// TestEnsureOpenShiftSharedResourcesNamespaceAppliesNamespaceCreationObjects verifies that objects returned by
+// NamespaceCreationObjects are created in a freshly created shared resources namespace.
+func TestEnsureOpenShiftSharedResourcesNamespaceAppliesNamespaceCreationObjects(t *testing.T) {
+	creator := &fakeNamespaceObjectCreator{created: map[string]runtime.Object{}}
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+			},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		NamespaceObjectCreator:                        creator,
+		NamespaceCreationObjects: func(namespace string) []runtime.Object {
+			return []runtime.Object{
+				&unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "networking.k8s.io/v1",
+					"kind":       "NetworkPolicy",
+					"metadata":   map[string]interface{}{"name": "default-deny"},
+				}},
+			}
+		},
+	}
+
+	if _, err := c.EnsureOpenShiftSharedResourcesNamespace(context.Background(), &BootstrapStepResult{Counts: map[string]int{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := creator.created["openshift/NetworkPolicy/default-deny"]; !ok {
+		t.Errorf("expected the hook's NetworkPolicy to be created in the openshift namespace, got: %v", creator.created)
+	}
+}
+
+func TestEnsureDefaultNamespaceServiceAccountRolesStopsOnceNamespaceAppears(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	getCount := 0
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		getCount++
+		if getCount < 3 {
+			return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), metav1.NamespaceDefault)
+		}
+		// Already marked initialized so the rest of ensureNamespaceServiceAccountRoleBindings short-circuits.
+		ns := &kapi.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        metav1.NamespaceDefault,
+				Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+			},
+		}
+		return true, ns, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		DefaultNamespaceWaitTimeout:                   time.Minute,
+	}
+
+	start := time.Now()
+	c.ensureDefaultNamespaceServiceAccountRoles(context.Background(), &BootstrapStepResult{Counts: map[string]int{}})
+	elapsed := time.Since(start)
+
+	if getCount != 3 {
+		t.Fatalf("expected exactly 3 Get calls before the namespace appeared, got %d", getCount)
+	}
+	if elapsed >= time.Minute {
+		t.Fatalf("expected the poll to exit as soon as the namespace appeared, took %v", elapsed)
+	}
+}
+
+func TestEnsureDefaultNamespaceServiceAccountRolesSkipsWhenDisabled(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		t.Fatalf("did not expect the default namespace to be looked up when initialization is skipped")
+		return false, nil, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{SkipDefaultNamespaceInitialization: true},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	c.ensureDefaultNamespaceServiceAccountRoles(context.Background(), result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("expected no errors when initialization is skipped, got %v", result.Errors)
+	}
+	if result.Counts["reconciled"] != 0 {
+		t.Fatalf("expected nothing to be reconciled when initialization is skipped, got %v", result.Counts)
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsForceReinitializes(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestCount++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:    osClient,
+		ForceReinitializeServiceAccountRoles: true,
+	}
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	if requestCount == 0 {
+		t.Fatalf("expected ForceReinitializeServiceAccountRoles to re-apply role bindings even though the namespace was already marked initialized")
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsHonorsCustomAnnotationOnRead(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestCount++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{ServiceAccountRolesInitializedAnnotation: "example.com/sa.initialized-roles"},
+		},
+	}
+	// Only the default annotation key is present, so the configured custom key must be treated as unset
+	// and the bindings re-applied rather than short-circuited.
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	if requestCount == 0 {
+		t.Fatalf("expected the configured annotation key to be checked instead of the default, triggering re-application")
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsVerifyRepairsDrift(t *testing.T) {
+	// Every List comes back empty, as if the role bindings had been deleted out from under an already
+	// "initialized" namespace. Every write is echoed back so AddRole succeeds.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{Bootstrap: configapi.BootstrapOptions{VerifyServiceAccountRoleBindings: true}},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	current, err := c.PrivilegedLoopbackKubernetesClientsetInternal.Core().Namespaces().Get("default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Annotations["openshift.io/sa.initialized-roles"] != "true" {
+		t.Fatalf("expected the annotation to remain recorded after repairing drift, got %v", current.Annotations)
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsVerifyDisabledStaysShortCircuited(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	c := &MasterConfig{PrivilegedLoopbackOpenShiftClient: osClient}
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	if requestCount != 0 {
+		t.Fatalf("expected the fast annotation check to stay the default with VerifyServiceAccountRoleBindings unset, got %d requests", requestCount)
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsHonorsCustomAnnotationOnWrite(t *testing.T) {
+	// Echo back whatever the client sent us so a successful Create/Update round-trips through the same
+	// codec the client used to encode it, letting AddRole complete without error.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	const customAnnotation = "example.com/sa.initialized-roles"
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{ServiceAccountRolesInitializedAnnotation: customAnnotation},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	current, err := c.PrivilegedLoopbackKubernetesClientsetInternal.Core().Namespaces().Get("default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Annotations[customAnnotation] != "true" {
+		t.Fatalf("expected the configured annotation key to be recorded after a successful run, got %v", current.Annotations)
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsUsesSharedResourcesOverride(t *testing.T) {
+	var createdRoleRefs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		var roleBinding authorizationapi.RoleBinding
+		if err := json.Unmarshal(body, &roleBinding); err == nil {
+			createdRoleRefs = append(createdRoleRefs, roleBinding.RoleRef.Name)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift"}}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+				SharedResourcesNamespaceRoleBindings: []configapi.SharedResourcesNamespaceRoleBinding{
+					{
+						Name:     "shared-viewers",
+						RoleName: "view",
+						Subjects: []kapi.ObjectReference{{Kind: authorizationapi.SystemGroupKind, Name: "system:authenticated"}},
+					},
+				},
+			},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	if len(createdRoleRefs) != 1 || createdRoleRefs[0] != "view" {
+		t.Fatalf("expected the configured shared resources role binding to be created instead of the default project bindings, got %v", createdRoleRefs)
+	}
+}
+
+func TestEnsureServiceAccountRoleBindingsFetchesNamespaceWhenNotProvided(t *testing.T) {
+	// Echo back whatever the client sent us so a successful Create round-trips through the same codec the
+	// client used to encode it, letting AddRole complete without error.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "newproject"}}
+	fakeKubeClient := fake.NewSimpleClientset(namespace)
+
+	if err := EnsureServiceAccountRoleBindings(osClient, fakeKubeClient, "newproject", EnsureServiceAccountRoleBindingsOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current, err := fakeKubeClient.Core().Namespaces().Get("newproject", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Annotations[defaultServiceAccountRolesInitializedAnnotation] != "true" {
+		t.Fatalf("expected the default annotation to be recorded after a successful run, got %v", current.Annotations)
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsRetriesAnnotationUpdateOnConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	fakeKubeClient := fake.NewSimpleClientset(namespace)
+
+	updateCount := 0
+	fakeKubeClient.PrependReactor("update", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		updateCount++
+		if updateCount == 1 {
+			return true, nil, kapierror.NewConflict(kapi.Resource("namespaces"), "default", errors.New("resource version mismatch"))
+		}
+		return false, nil, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	if updateCount < 2 {
+		t.Fatalf("expected the update to be retried after the conflict, got %d attempts", updateCount)
+	}
+
+	current, err := fakeKubeClient.Core().Namespaces().Get("default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Annotations["openshift.io/sa.initialized-roles"] != "true" {
+		t.Fatalf("expected the annotation to be persisted once the conflict cleared, got %v", current.Annotations)
+	}
+}
+
+func TestReconcileSecurityContextConstraintNoChange(t *testing.T) {
+	priority := int32(10)
+	scc := securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Priority:   &priority,
+		Volumes:    []securityapi.FSType{securityapi.FSTypeEmptyDir},
+		Users:      []string{"system:serviceaccount:kube-system:generic-garbage-collector"},
+	}
+	existing := scc
+	client := newFakeSCCClient(&existing)
+
+	if err := reconcileSecurityContextConstraint(client, scc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated != nil {
+		t.Fatalf("expected no update when the SCC already matches the bootstrap definition")
+	}
+}
+
+func TestReconcileSecurityContextConstraintDrift(t *testing.T) {
+	oldPriority := int32(5)
+	newPriority := int32(10)
+	desired := securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Priority:   &newPriority,
+		Volumes:    []securityapi.FSType{securityapi.FSTypeEmptyDir},
+	}
+	existing := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Priority:   &oldPriority,
+		Volumes:    []securityapi.FSType{securityapi.FSTypeEmptyDir},
+	}
+	client := newFakeSCCClient(existing)
+
+	if err := reconcileSecurityContextConstraint(client, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update when priority drifted")
+	}
+	if *client.updated.Priority != newPriority {
+		t.Errorf("expected priority %d, got %d", newPriority, *client.updated.Priority)
+	}
+}
+
+func TestReconcileSecurityContextConstraintUnionPreservesAdminSubjects(t *testing.T) {
+	// The bootstrap definition adds a new group that isn't present yet, which should trigger an update,
+	// but the union must keep the admin-added user around rather than dropping it.
+	desired := securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Users:      []string{"system:serviceaccount:kube-system:generic-garbage-collector"},
+		Groups:     []string{"system:authenticated", "system:cluster-admins"},
+	}
+	existing := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Users:      []string{"system:serviceaccount:kube-system:generic-garbage-collector", "admin-added-user"},
+		Groups:     []string{"system:authenticated"},
+	}
+	client := newFakeSCCClient(existing)
+
+	if err := reconcileSecurityContextConstraint(client, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update since the bootstrap definition introduced a new group")
+	}
+	found := false
+	for _, u := range client.updated.Users {
+		if u == "admin-added-user" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected admin-added-user to survive reconciliation, got %v", client.updated.Users)
+	}
+}
+
+// TestReconcileSecurityContextConstraintUnionPreservesAdminAddedCapabilities verifies that an admin-added
+// entry in AllowedCapabilities survives reconciliation, unioned in alongside the bootstrap definition's own
+// capability, rather than being overwritten by it.
+func TestReconcileSecurityContextConstraintUnionPreservesAdminAddedCapabilities(t *testing.T) {
+	desired := securityapi.SecurityContextConstraints{
+		ObjectMeta:          metav1.ObjectMeta{Name: "restricted"},
+		AllowedCapabilities: []kapi.Capability{"KILL"},
+	}
+	existing := &securityapi.SecurityContextConstraints{
+		ObjectMeta:          metav1.ObjectMeta{Name: "restricted"},
+		AllowedCapabilities: []kapi.Capability{"NET_BIND_SERVICE"},
+	}
+	client := newFakeSCCClient(existing)
+
+	if err := reconcileSecurityContextConstraint(client, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update since the bootstrap definition introduced a new capability")
+	}
+	got := sets.NewString()
+	for _, c := range client.updated.AllowedCapabilities {
+		got.Insert(string(c))
+	}
+	if !got.HasAll("KILL", "NET_BIND_SERVICE") {
+		t.Errorf("expected both the admin-added and bootstrap capabilities to survive, got %v", client.updated.AllowedCapabilities)
+	}
+}
+
+// TestReconcileSecurityContextConstraintUnionPreservesAdminAddedVolumes verifies that an admin-added entry in
+// Volumes survives reconciliation, unioned in alongside the bootstrap definition's own volume plugin, rather
+// than being overwritten by it.
+func TestReconcileSecurityContextConstraintUnionPreservesAdminAddedVolumes(t *testing.T) {
+	desired := securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Volumes:    []securityapi.FSType{securityapi.FSTypeEmptyDir},
+	}
+	existing := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Volumes:    []securityapi.FSType{securityapi.FSTypeSecret},
+	}
+	client := newFakeSCCClient(existing)
+
+	if err := reconcileSecurityContextConstraint(client, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update since the bootstrap definition introduced a new volume plugin")
+	}
+	got := sets.NewString()
+	for _, v := range client.updated.Volumes {
+		got.Insert(string(v))
+	}
+	if !got.HasAll(string(securityapi.FSTypeEmptyDir), string(securityapi.FSTypeSecret)) {
+		t.Errorf("expected both the admin-added and bootstrap volume plugins to survive, got %v", client.updated.Volumes)
+	}
+}
+
+// TestReconcileSecurityContextConstraintSetsScalarFieldsAuthoritatively verifies that Priority - a scalar
+// field with no meaningful "union" - is set authoritatively from the bootstrap definition even though list
+// fields on the same SCC are merged.
+func TestReconcileSecurityContextConstraintSetsScalarFieldsAuthoritatively(t *testing.T) {
+	oldPriority := int32(5)
+	newPriority := int32(10)
+	desired := securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Priority:   &newPriority,
+		Volumes:    []securityapi.FSType{securityapi.FSTypeEmptyDir},
+	}
+	existing := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Priority:   &oldPriority,
+		Volumes:    []securityapi.FSType{securityapi.FSTypeSecret},
+	}
+	client := newFakeSCCClient(existing)
+
+	if err := reconcileSecurityContextConstraint(client, desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update")
+	}
+	if *client.updated.Priority != newPriority {
+		t.Errorf("expected priority to be set authoritatively to %d, got %d", newPriority, *client.updated.Priority)
+	}
+	got := sets.NewString()
+	for _, v := range client.updated.Volumes {
+		got.Insert(string(v))
+	}
+	if !got.HasAll(string(securityapi.FSTypeEmptyDir), string(securityapi.FSTypeSecret)) {
+		t.Errorf("expected volumes to still be unioned alongside the authoritative priority change, got %v", client.updated.Volumes)
+	}
+}
+
+func TestVerifyBootstrapSCCOwnershipAcceptsBootstrapOwnedSCC(t *testing.T) {
+	client := newFakeSCCClient(&securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Labels: map[string]string{bootstrapOwnedSCCLabel: "true"}},
+	})
+
+	if err := verifyBootstrapSCCOwnership(client, "restricted"); err != nil {
+		t.Errorf("expected a bootstrap-owned SCC to pass verification, got %v", err)
+	}
+}
+
+func TestVerifyBootstrapSCCOwnershipRejectsUnownedSCC(t *testing.T) {
+	client := newFakeSCCClient(&securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+	})
+
+	if err := verifyBootstrapSCCOwnership(client, "restricted"); err == nil {
+		t.Errorf("expected an SCC missing the bootstrap-owned label to fail verification")
+	}
+}
+
+func TestVerifyBootstrapSCCOwnershipPropagatesErrorForMissingSCC(t *testing.T) {
+	client := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+
+	err := verifyBootstrapSCCOwnership(client, "restricted")
+	if err == nil {
+		t.Fatalf("expected an error for a missing SCC")
+	}
+	if !kapierror.IsNotFound(err) {
+		t.Errorf("expected a NotFound error to be propagated unchanged, got %v", err)
+	}
+}
+
+func TestPruneStaleSecurityContextConstraintsDeletesStaleBootstrapOwnedSCC(t *testing.T) {
+	client := newFakeSCCClient(&securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Labels: map[string]string{bootstrapOwnedSCCLabel: "true"}},
+	})
+	stale := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "old-bootstrap-scc", Labels: map[string]string{bootstrapOwnedSCCLabel: "true"}},
+	}
+	client.sccs[stale.Name] = stale
+
+	if err := pruneStaleSecurityContextConstraints(client, sets.NewString("restricted")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := client.sccs["old-bootstrap-scc"]; ok {
+		t.Errorf("expected old-bootstrap-scc to be pruned")
+	}
+	if _, ok := client.sccs["restricted"]; !ok {
+		t.Errorf("expected restricted to survive pruning since it's still part of the bootstrap set")
+	}
+}
+
+func TestPruneStaleSecurityContextConstraintsIgnoresUserCreatedSCC(t *testing.T) {
+	client := newFakeSCCClient(&securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Labels: map[string]string{bootstrapOwnedSCCLabel: "true"}},
+	})
+	userCreated := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-custom-scc"},
+	}
+	client.sccs[userCreated.Name] = userCreated
+
+	if err := pruneStaleSecurityContextConstraints(client, sets.NewString("restricted")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := client.sccs["admin-custom-scc"]; !ok {
+		t.Errorf("expected user-created SCC without the bootstrap-owned label to survive pruning")
+	}
+	if len(client.deleted) != 0 {
+		t.Errorf("expected no deletions, got %v", client.deleted)
+	}
+}
+
+func TestValidateBootstrapSecurityContextConstraintsCatchesInvalidSCC(t *testing.T) {
+	valid := securityapi.SecurityContextConstraints{
+		ObjectMeta:         metav1.ObjectMeta{Name: "restricted"},
+		Volumes:            []securityapi.FSType{securityapi.FSTypeEmptyDir},
+		RunAsUser:          securityapi.RunAsUserStrategyOptions{Type: securityapi.RunAsUserStrategyRunAsAny},
+		SELinuxContext:     securityapi.SELinuxContextStrategyOptions{Type: securityapi.SELinuxStrategyRunAsAny},
+		FSGroup:            securityapi.FSGroupStrategyOptions{Type: securityapi.FSGroupStrategyRunAsAny},
+		SupplementalGroups: securityapi.SupplementalGroupsStrategyOptions{Type: securityapi.SupplementalGroupsStrategyRunAsAny},
+	}
+	invalid := securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+		// "none" combined with another volume type is rejected by ValidateSecurityContextConstraints.
+		Volumes:            []securityapi.FSType{securityapi.FSTypeNone, securityapi.FSTypeEmptyDir},
+		RunAsUser:          securityapi.RunAsUserStrategyOptions{Type: securityapi.RunAsUserStrategyRunAsAny},
+		SELinuxContext:     securityapi.SELinuxContextStrategyOptions{Type: securityapi.SELinuxStrategyRunAsAny},
+		FSGroup:            securityapi.FSGroupStrategyOptions{Type: securityapi.FSGroupStrategyRunAsAny},
+		SupplementalGroups: securityapi.SupplementalGroupsStrategyOptions{Type: securityapi.SupplementalGroupsStrategyRunAsAny},
+	}
+
+	err := validateBootstrapSecurityContextConstraints([]securityapi.SecurityContextConstraints{valid, invalid})
+	if err == nil {
+		t.Fatalf("expected an aggregate error naming the invalid SCC")
+	}
+	if !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected the error to name the invalid SCC %q, got %v", "broken", err)
+	}
+	if strings.Contains(err.Error(), "\"restricted\"") {
+		t.Errorf("expected the valid SCC to be omitted from the error, got %v", err)
+	}
+}
+
+func TestValidateBootstrapSecurityContextConstraintsAllValid(t *testing.T) {
+	valid := securityapi.SecurityContextConstraints{
+		ObjectMeta:         metav1.ObjectMeta{Name: "restricted"},
+		Volumes:            []securityapi.FSType{securityapi.FSTypeEmptyDir},
+		RunAsUser:          securityapi.RunAsUserStrategyOptions{Type: securityapi.RunAsUserStrategyRunAsAny},
+		SELinuxContext:     securityapi.SELinuxContextStrategyOptions{Type: securityapi.SELinuxStrategyRunAsAny},
+		FSGroup:            securityapi.FSGroupStrategyOptions{Type: securityapi.FSGroupStrategyRunAsAny},
+		SupplementalGroups: securityapi.SupplementalGroupsStrategyOptions{Type: securityapi.SupplementalGroupsStrategyRunAsAny},
+	}
+
+	if err := validateBootstrapSecurityContextConstraints([]securityapi.SecurityContextConstraints{valid}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// fakeRoleClient is a minimal in-memory osclient.RoleInterface for exercising reconcileNamespacedRole
+// without a real apiserver.
+type fakeRoleClient struct {
+	roles   map[string]*authorizationapi.Role
+	updated *authorizationapi.Role
+	created *authorizationapi.Role
+	// createConflicts, when greater than zero, makes that many calls to Create return a conflict error before
+	// the next Create succeeds, for exercising createNamespacedRoleWithRetry's retry behavior.
+	createConflicts int
+}
+
+func newFakeRoleClient(existing *authorizationapi.Role) *fakeRoleClient {
+	client := &fakeRoleClient{roles: map[string]*authorizationapi.Role{}}
+	if existing != nil {
+		client.roles[existing.Name] = existing
+	}
+	return client
+}
+
+func (f *fakeRoleClient) List(opts metav1.ListOptions) (*authorizationapi.RoleList, error) {
+	return nil, nil
+}
+func (f *fakeRoleClient) Get(name string, options metav1.GetOptions) (*authorizationapi.Role, error) {
+	role, ok := f.roles[name]
+	if !ok {
+		return nil, kapierror.NewNotFound(kapi.Resource("roles"), name)
+	}
+	return role, nil
+}
+func (f *fakeRoleClient) Create(role *authorizationapi.Role) (*authorizationapi.Role, error) {
+	if f.createConflicts > 0 {
+		f.createConflicts--
+		return nil, kapierror.NewConflict(kapi.Resource("roles"), role.Name, fmt.Errorf("conflict"))
+	}
+	f.roles[role.Name] = role
+	f.created = role
+	return role, nil
+}
+func (f *fakeRoleClient) Update(role *authorizationapi.Role) (*authorizationapi.Role, error) {
+	f.roles[role.Name] = role
+	f.updated = role
+	return role, nil
+}
+func (f *fakeRoleClient) Delete(name string) error { delete(f.roles, name); return nil }
+
+func TestReconcileNamespacedRoleNoChange(t *testing.T) {
+	existing := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get", "list"), Resources: sets.NewString("pods")}},
+	}
+	client := newFakeRoleClient(existing)
+
+	desired := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get", "list"), Resources: sets.NewString("pods")}},
+	}
+	if err := reconcileNamespacedRole(client, desired, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated != nil {
+		t.Errorf("expected no update when rules already match, got %v", client.updated)
+	}
+}
+
+func TestReconcileNamespacedRoleUpdatesOnDrift(t *testing.T) {
+	existing := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get"), Resources: sets.NewString("pods")}},
+	}
+	client := newFakeRoleClient(existing)
+
+	desired := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get", "list"), Resources: sets.NewString("pods")}},
+	}
+	if err := reconcileNamespacedRole(client, desired, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update when the bootstrap rules drifted from existing")
+	}
+	if len(client.updated.Rules) != 2 {
+		t.Errorf("expected existing rule plus new bootstrap rule to be unioned, got %v", client.updated.Rules)
+	}
+}
+
+// TestReconcileNamespacedRoleRetriesConflictOnCreate verifies that reconcileNamespacedRole's Create path
+// survives a conflict on its first attempt and ultimately creates the role.
+func TestReconcileNamespacedRoleRetriesConflictOnCreate(t *testing.T) {
+	client := newFakeRoleClient(nil)
+	client.createConflicts = 1
+
+	desired := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get"), Resources: sets.NewString("pods")}},
+	}
+	if err := reconcileNamespacedRole(client, desired, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.created == nil || client.created.Name != "admin" {
+		t.Fatalf("expected the role to be created after the conflict was retried, got %v", client.created)
+	}
+}
+
+// fakeRoleBindingClient is a minimal in-memory osclient.RoleBindingInterface for exercising
+// reconcileNamespacedRoleBinding without a real apiserver.
+type fakeRoleBindingClient struct {
+	roleBindings map[string]*authorizationapi.RoleBinding
+	updated      *authorizationapi.RoleBinding
+	created      *authorizationapi.RoleBinding
+	// createConflicts, when greater than zero, makes that many calls to Create return a conflict error before
+	// the next Create succeeds, for exercising createNamespacedRoleBindingWithRetry's retry behavior.
+	createConflicts int
+}
+
+func newFakeRoleBindingClient(existing *authorizationapi.RoleBinding) *fakeRoleBindingClient {
+	client := &fakeRoleBindingClient{roleBindings: map[string]*authorizationapi.RoleBinding{}}
+	if existing != nil {
+		client.roleBindings[existing.Name] = existing
+	}
+	return client
+}
+
+func (f *fakeRoleBindingClient) List(opts metav1.ListOptions) (*authorizationapi.RoleBindingList, error) {
+	return nil, nil
+}
+func (f *fakeRoleBindingClient) Get(name string, options metav1.GetOptions) (*authorizationapi.RoleBinding, error) {
+	roleBinding, ok := f.roleBindings[name]
+	if !ok {
+		return nil, kapierror.NewNotFound(kapi.Resource("rolebindings"), name)
+	}
+	return roleBinding, nil
+}
+func (f *fakeRoleBindingClient) Create(roleBinding *authorizationapi.RoleBinding) (*authorizationapi.RoleBinding, error) {
+	if f.createConflicts > 0 {
+		f.createConflicts--
+		return nil, kapierror.NewConflict(kapi.Resource("rolebindings"), roleBinding.Name, fmt.Errorf("conflict"))
+	}
+	f.roleBindings[roleBinding.Name] = roleBinding
+	f.created = roleBinding
+	return roleBinding, nil
+}
+func (f *fakeRoleBindingClient) Update(roleBinding *authorizationapi.RoleBinding) (*authorizationapi.RoleBinding, error) {
+	f.roleBindings[roleBinding.Name] = roleBinding
+	f.updated = roleBinding
+	return roleBinding, nil
+}
+func (f *fakeRoleBindingClient) Delete(name string) error { delete(f.roleBindings, name); return nil }
+
+func TestReconcileNamespacedRoleBindingNoChange(t *testing.T) {
+	existing := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+		RoleRef:    kapi.ObjectReference{Name: "admin"},
+		Subjects:   []kapi.ObjectReference{{Kind: "SystemGroup", Name: "system:cluster-admins"}},
+	}
+	client := newFakeRoleBindingClient(existing)
+
+	desired := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+		RoleRef:    kapi.ObjectReference{Name: "admin"},
+		Subjects:   []kapi.ObjectReference{{Kind: "SystemGroup", Name: "system:cluster-admins"}},
+	}
+	if err := reconcileNamespacedRoleBinding(client, desired, true, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated != nil {
+		t.Errorf("expected no update when subjects and roleRef already match, got %v", client.updated)
+	}
+}
+
+// TestReconcileNamespacedRoleBindingRetriesConflictOnCreate verifies that reconcileNamespacedRoleBinding's
+// Create path survives a conflict on its first attempt and ultimately creates the rolebinding.
+func TestReconcileNamespacedRoleBindingRetriesConflictOnCreate(t *testing.T) {
+	client := newFakeRoleBindingClient(nil)
+	client.createConflicts = 1
+
+	desired := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+		RoleRef:    kapi.ObjectReference{Name: "admin"},
+		Subjects:   []kapi.ObjectReference{{Kind: "SystemGroup", Name: "system:cluster-admins"}},
+	}
+	if err := reconcileNamespacedRoleBinding(client, desired, true, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.created == nil || client.created.Name != "admins" {
+		t.Fatalf("expected the rolebinding to be created after the conflict was retried, got %v", client.created)
+	}
+}
+
+func TestReconcileNamespacedRoleBindingUpdatesOnDrift(t *testing.T) {
+	existing := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+		RoleRef:    kapi.ObjectReference{Name: "admin"},
+		Subjects: []kapi.ObjectReference{
+			{Kind: "SystemGroup", Name: "system:cluster-admins"},
+			{Kind: "User", Name: "admin-added-user"},
+		},
+	}
+	client := newFakeRoleBindingClient(existing)
+
+	desired := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+		RoleRef:    kapi.ObjectReference{Name: "admin"},
+		Subjects: []kapi.ObjectReference{
+			{Kind: "SystemGroup", Name: "system:cluster-admins"},
+			{Kind: "ServiceAccount", Name: "builder", Namespace: "openshift-infra"},
+		},
+	}
+	if err := reconcileNamespacedRoleBinding(client, desired, true, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated == nil {
+		t.Fatalf("expected an update when the bootstrap subjects drifted from existing")
+	}
+	found := false
+	for _, s := range client.updated.Subjects {
+		if s.Name == "admin-added-user" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected admin-added-user to survive reconciliation, got %v", client.updated.Subjects)
+	}
+}
+
+func TestReconcileNamespacedRoleBindingRecreatesOnRoleRefChange(t *testing.T) {
+	existing := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "builders"},
+		RoleRef:    kapi.ObjectReference{Name: "old-role"},
+		Subjects: []kapi.ObjectReference{
+			{Kind: "ServiceAccount", Name: "builder"},
+			{Kind: "User", Name: "admin-added-user"},
+		},
+	}
+	client := newFakeRoleBindingClient(existing)
+
+	desired := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "builders"},
+		RoleRef:    kapi.ObjectReference{Name: "new-role"},
+		Subjects:   []kapi.ObjectReference{{Kind: "ServiceAccount", Name: "builder"}},
+	}
+	if err := reconcileNamespacedRoleBinding(client, desired, true, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.updated != nil {
+		t.Errorf("expected no in-place Update of an immutable RoleRef, got %v", client.updated)
+	}
+	if client.created == nil {
+		t.Fatalf("expected the binding to be recreated with the new roleRef")
+	}
+	if client.created.RoleRef.Name != "new-role" {
+		t.Errorf("expected the recreated binding to have the new roleRef, got %v", client.created.RoleRef)
+	}
+	foundBuilder, foundAdmin := false, false
+	for _, s := range client.created.Subjects {
+		if s.Name == "builder" {
+			foundBuilder = true
+		}
+		if s.Name == "admin-added-user" {
+			foundAdmin = true
+		}
+	}
+	if !foundBuilder || !foundAdmin {
+		t.Errorf("expected the recreated binding to preserve merged subjects, got %v", client.created.Subjects)
+	}
+}
+
+func TestReconcileNamespacedRoleBindingLeavesRoleRefChangeAloneWithoutOptIn(t *testing.T) {
+	existing := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "builders"},
+		RoleRef:    kapi.ObjectReference{Name: "old-role"},
+		Subjects:   []kapi.ObjectReference{{Kind: "ServiceAccount", Name: "builder"}},
+	}
+	client := newFakeRoleBindingClient(existing)
+
+	desired := &authorizationapi.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "builders"},
+		RoleRef:    kapi.ObjectReference{Name: "new-role"},
+		Subjects:   []kapi.ObjectReference{{Kind: "ServiceAccount", Name: "builder"}},
+	}
+	if err := reconcileNamespacedRoleBinding(client, desired, false, false, ioutil.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.created != nil {
+		t.Errorf("expected no delete+recreate without recreateOnRoleRefChange, got %v", client.created)
+	}
+	if client.updated == nil || client.updated.RoleRef.Name != "new-role" {
+		t.Errorf("expected the caller's requested in-place RoleRef update to still be attempted, got %v", client.updated)
+	}
+}
+
+// TestParallelizeReducesWallClockTime demonstrates that parallelize actually overlaps the simulated latency
+// of its pieces rather than running them serially: 16 pieces at 20ms each would take ~320ms serially, but
+// with 8 workers should complete in roughly 2 batches, well under half that.
+func TestParallelizeReducesWallClockTime(t *testing.T) {
+	const pieces = 16
+	const workers = 8
+	const latency = 20 * time.Millisecond
+
+	start := time.Now()
+	errs := parallelize(pieces, workers, 1, func(i int) error {
+		time.Sleep(latency)
+		return nil
+	})
+	elapsed := time.Since(start)
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	serialEstimate := time.Duration(pieces) * latency
+	if elapsed >= serialEstimate/2 {
+		t.Errorf("expected parallelize with %d workers to run in well under half the serial time %v, took %v", workers, serialEstimate, elapsed)
+	}
+}
+
+func TestParallelizeAggregatesErrorsInOrder(t *testing.T) {
+	errs := parallelize(5, 3, 1, func(i int) error {
+		if i%2 == 0 {
+			return fmt.Errorf("piece %d failed", i)
+		}
+		return nil
+	})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %d: %v", len(errs), errs)
+	}
+	expected := []string{"piece 0 failed", "piece 2 failed", "piece 4 failed"}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("expected errors in deterministic index order, got %q at position %d, want %q", err.Error(), i, expected[i])
+		}
+	}
+}
+
+func TestParallelizeRespectsBatchSize(t *testing.T) {
+	const pieces = 6
+	seen := make([]int, pieces)
+	errs := parallelize(pieces, 1, 2, func(i int) error {
+		seen[i]++
+		return nil
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for i, count := range seen {
+		if count != 1 {
+			t.Errorf("expected piece %d to run exactly once, ran %d times", i, count)
+		}
+	}
+}
+
+func TestReconcileNamespacedRoleDryRunDoesNotMutate(t *testing.T) {
+	existing := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get"), Resources: sets.NewString("pods")}},
+	}
+	client := newFakeRoleClient(existing)
+
+	desired := &authorizationapi.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Rules:      []authorizationapi.PolicyRule{{Verbs: sets.NewString("get", "list"), Resources: sets.NewString("pods")}},
+	}
+	out := &bytes.Buffer{}
+	if err := reconcileNamespacedRole(client, desired, true, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.updated != nil {
+		t.Errorf("expected dry-run to skip the Update call, got %v", client.updated)
+	}
+	if out.Len() == 0 {
+		t.Errorf("expected the planned update to be described on the output writer")
+	}
+}
+
+func TestRetryOnTransientErrorHonorsCancellation(t *testing.T) {
+	c := &MasterConfig{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := c.retryOnTransientError(ctx, func() error {
+		calls++
+		return kapierror.NewServerTimeout(kapi.Resource("namespaces"), "create", 0)
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled once ctx was already cancelled, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected fn not to be called once ctx was already cancelled, got %d calls", calls)
+	}
+}
+
+func TestRunBootstrapStepWithTimeoutAbandonsBlockedStep(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{Bootstrap: configapi.BootstrapOptions{StepTimeoutSeconds: 1}},
+		},
+	}
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+
+	blocked := make(chan struct{})
+	err := c.runBootstrapStepWithTimeout(context.Background(), result, func(stepCtx context.Context) error {
+		// Simulate a client call that blocks on a wedged apiserver/etcd and never itself observes stepCtx.
+		<-blocked
+		return nil
+	})
+	close(blocked)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0] != context.DeadlineExceeded {
+		t.Errorf("expected the deadline-exceeded error to be recorded on the step result, got %v", result.Errors)
+	}
+}
+
+func TestRunBootstrapStepWithTimeoutReturnsFnResultWhenItFinishesInTime(t *testing.T) {
+	c := &MasterConfig{}
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+
+	err := c.runBootstrapStepWithTimeout(context.Background(), result, func(stepCtx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no errors recorded, got %v", result.Errors)
+	}
+}
+
+func TestBootstrapStepTimeoutDefaultsWhenUnset(t *testing.T) {
+	c := &MasterConfig{}
+
+	if got := c.bootstrapStepTimeout(); got != defaultBootstrapStepTimeout {
+		t.Errorf("expected default timeout %v, got %v", defaultBootstrapStepTimeout, got)
+	}
+}
+
+func TestRegisterBootstrapMetricsIsIdempotent(t *testing.T) {
+	// Registering twice (as happens across multiple MasterConfig instances in a test binary) must not panic
+	// with a duplicate-registration error from the Prometheus client.
+	RegisterBootstrapMetrics()
+	RegisterBootstrapMetrics()
+}
+
+func TestObserveBootstrapStepReturnsFnError(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+
+	err := observeBootstrapStep("test-step", func() error {
+		calls++
+		return boom
+	})
+	if err != boom {
+		t.Fatalf("expected the error from fn to be returned unchanged, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected fn to be called exactly once, got %d", calls)
+	}
+}
+
+func TestValidateSharedResourcesNamespacesRejectsInfraNamespaceCollision(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace:   "openshift",
+				OpenShiftInfrastructureNamespace:    "openshift-infra",
+				AdditionalSharedResourcesNamespaces: []string{"openshift-infra"},
+			},
+		},
+	}
+	if err := c.validateSharedResourcesNamespaces(); err == nil {
+		t.Fatalf("expected an error when an additional shared resources namespace collides with the infra namespace")
+	}
+}
+
+func TestValidateSharedResourcesNamespacesRejectsDuplicates(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace:   "openshift",
+				OpenShiftInfrastructureNamespace:    "openshift-infra",
+				AdditionalSharedResourcesNamespaces: []string{"openshift"},
+			},
+		},
+	}
+	if err := c.validateSharedResourcesNamespaces(); err == nil {
+		t.Fatalf("expected an error when an additional shared resources namespace duplicates the primary one")
+	}
+}
+
+func TestValidateBootstrapNamespaceConfigAcceptsWellFormedConfig(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+				OpenShiftInfrastructureNamespace:  "openshift-infra",
+				BootstrapPolicyFile:               "/etc/origin/master/policy.json",
+			},
+		},
+	}
+	if err := c.validateBootstrapNamespaceConfig(); err != nil {
+		t.Errorf("unexpected error for a well-formed config: %v", err)
+	}
+}
+
+func TestValidateBootstrapNamespaceConfigRejectsEmptyFields(t *testing.T) {
+	c := &MasterConfig{}
+
+	err := c.validateBootstrapNamespaceConfig()
+	if err == nil {
+		t.Fatalf("expected an error for an entirely empty PolicyConfig")
+	}
+	for _, field := range []string{"openShiftInfrastructureNamespace", "openShiftSharedResourcesNamespace", "bootstrapPolicyFile"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("expected the aggregate error to mention %q, got %v", field, err)
+		}
+	}
+}
+
+func TestValidateBootstrapNamespaceConfigRejectsInvalidDNSNames(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace:   "openshift",
+				OpenShiftInfrastructureNamespace:    "Not_A_Valid_Namespace",
+				AdditionalSharedResourcesNamespaces: []string{"also not valid"},
+				BootstrapPolicyFile:                 "/etc/origin/master/policy.json",
+			},
+		},
+	}
+
+	err := c.validateBootstrapNamespaceConfig()
+	if err == nil {
+		t.Fatalf("expected an error for an invalid DNS label namespace name")
+	}
+	if !strings.Contains(err.Error(), "openShiftInfrastructureNamespace") {
+		t.Errorf("expected the aggregate error to name the invalid field, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "additionalSharedResourcesNamespaces[0]") {
+		t.Errorf("expected the aggregate error to name the invalid additional namespace by index, got %v", err)
+	}
+}
+
+func TestValidateBootstrapNamespaceConfigHonorsInfraNamespaceEnvOverride(t *testing.T) {
+	os.Setenv(infraNamespaceEnvVar, "openshift-infra-override")
+	defer os.Unsetenv(infraNamespaceEnvVar)
+
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace: "openshift",
+				OpenShiftInfrastructureNamespace:  "",
+				BootstrapPolicyFile:               "/etc/origin/master/policy.json",
+			},
+		},
+	}
+
+	if err := c.validateBootstrapNamespaceConfig(); err != nil {
+		t.Errorf("expected the env override to satisfy validation even though the configured value is empty, got %v", err)
+	}
+}
+
+func TestEnsureBootstrapPolicyFailsFastOnInvalidPolicyConfig(t *testing.T) {
+	c := &MasterConfig{}
+
+	result, err := c.EnsureBootstrapPolicy(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error for an entirely empty PolicyConfig")
+	}
+	if result == nil {
+		t.Fatalf("expected a non-nil result even on early validation failure")
+	}
+	if c.BootstrapComplete() {
+		t.Errorf("expected BootstrapComplete to stay false when policy config validation fails")
+	}
+}
+
+func TestEnsureOpenShiftSharedResourcesNamespaceCreatesAdditionalNamespaces(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clientgotesting.GetAction).GetName()
+		return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), name)
+	})
+
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftSharedResourcesNamespace:   "openshift",
+				OpenShiftInfrastructureNamespace:    "openshift-infra",
+				AdditionalSharedResourcesNamespaces: []string{"openshift-shared-2"},
+			},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+	}
+
+	if _, err := c.EnsureOpenShiftSharedResourcesNamespace(context.Background(), &BootstrapStepResult{Counts: map[string]int{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := sets.NewString()
+	for _, action := range fakeKubeClient.Actions() {
+		if action.GetVerb() != "create" {
+			continue
+		}
+		created.Insert(action.(clientgotesting.CreateAction).GetObject().(*kapi.Namespace).Name)
+	}
+	if !created.HasAll("openshift", "openshift-shared-2") {
+		t.Errorf("expected both the primary and additional shared resources namespaces to be created, got %v", created.List())
+	}
+}
+
+func TestValidateBootstrapPolicyFileRejectsTruncatedFile(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "bootstrap-policy")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(`{"kind":"Template","apiVersion":"v1","objects":[`); err != nil {
+		t.Fatalf("unexpected error writing temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	if err := validateBootstrapPolicyFile(tmpFile.Name()); err == nil {
+		t.Fatalf("expected an error for a truncated bootstrap policy file")
+	}
+}
+
+func TestValidateBootstrapPolicyFileRejectsEmptyTemplate(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "bootstrap-policy")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(`{"kind":"Template","apiVersion":"v1","objects":[]}`); err != nil {
+		t.Fatalf("unexpected error writing temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	if err := validateBootstrapPolicyFile(tmpFile.Name()); err == nil {
+		t.Fatalf("expected an error for a template with no policy objects")
+	}
+}
+
+func TestValidateBootstrapPolicyFileRejectsMissingFile(t *testing.T) {
+	if err := validateBootstrapPolicyFile("/nonexistent/bootstrap-policy.json"); err == nil {
+		t.Fatalf("expected an error for a missing bootstrap policy file")
+	}
+}
+
+// fakeClusterPolicyRegistry is a minimal clusterpolicyregistry.Registry for verifying that
+// clusterPolicyRegistryLazy caches the registry instead of rebuilding it on every call.
+type fakeClusterPolicyRegistry struct{}
+
+func (fakeClusterPolicyRegistry) ListClusterPolicies(ctx apirequest.Context, options *metainternal.ListOptions) (*authorizationapi.ClusterPolicyList, error) {
+	return nil, nil
+}
+func (fakeClusterPolicyRegistry) GetClusterPolicy(ctx apirequest.Context, id string, options *metav1.GetOptions) (*authorizationapi.ClusterPolicy, error) {
+	return nil, nil
+}
+func (fakeClusterPolicyRegistry) CreateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (fakeClusterPolicyRegistry) UpdateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (fakeClusterPolicyRegistry) DeleteClusterPolicy(ctx apirequest.Context, id string) error {
+	return nil
+}
+
+func TestClusterRolesToReconcileIncludesConfiguredKnownRoles(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				AlwaysReconcileClusterRoles: []string{"cluster-admin", "not-a-real-role"},
+			},
+		},
+	}
+
+	rolesToReconcile := sets.NewString(c.clusterRolesToReconcile()...)
+
+	if !rolesToReconcile.Has("cluster-admin") {
+		t.Errorf("expected the configured known role cluster-admin to be included, got %v", rolesToReconcile.List())
+	}
+	if rolesToReconcile.Has("not-a-real-role") {
+		t.Errorf("expected the unrecognized role to be dropped, got %v", rolesToReconcile.List())
+	}
+	if !rolesToReconcile.Has("system:discovery") {
+		t.Errorf("expected the built-in discovery role to still be included, got %v", rolesToReconcile.List())
+	}
+}
+
+// TestReconcileComponentAuthorizationRulesReconcilesClusterRolesAndBindings verifies that the extracted
+// ReconcileComponentAuthorizationRules reconciles cluster roles and rolebindings purely from the options
+// passed to it - no MasterConfig required - so it can be driven by an `oc adm` command against a live
+// cluster. Namespaced role reconciliation is disabled here to keep the test focused on that behavior.
+func TestReconcileComponentAuthorizationRulesReconcilesClusterRolesAndBindings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "clusterrolebindings"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "clusterroles"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	registry := staleClusterPolicyRegistry{policy: &authorizationapi.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: authorizationapi.PolicyName},
+	}}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	ReconcileComponentAuthorizationRules(context.Background(), ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry:    registry,
+		OpenShiftClient:          osClient,
+		ReconcileNamespacedRoles: func(clusterPolicyMissing bool) bool { return false },
+		RolesToReconcile:         []string{bootstrappolicy.DiscoveryRoleName},
+		Out:                      ioutil.Discard,
+	}, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.Counts["rolesReconciled"] != 1 {
+		t.Errorf("expected 1 role reconciled, got %v", result.Counts)
+	}
+	if result.Counts["roleBindingsReconciled"] != 1 {
+		t.Errorf("expected 1 rolebinding reconciled, got %v", result.Counts)
+	}
+}
+
+// flappingClusterPolicyRegistry returns NotFound from GetClusterPolicy for the first notFoundCalls calls,
+// then a present policy thereafter, for simulating a transient Get that briefly misreports cluster policy
+// as missing.
+type flappingClusterPolicyRegistry struct {
+	notFoundCalls int
+	calls         int
+}
+
+func (r *flappingClusterPolicyRegistry) ListClusterPolicies(ctx apirequest.Context, options *metainternal.ListOptions) (*authorizationapi.ClusterPolicyList, error) {
+	return nil, nil
+}
+func (r *flappingClusterPolicyRegistry) GetClusterPolicy(ctx apirequest.Context, id string, options *metav1.GetOptions) (*authorizationapi.ClusterPolicy, error) {
+	r.calls++
+	if r.calls <= r.notFoundCalls {
+		return nil, kapierror.NewNotFound(authorizationapi.Resource("clusterpolicy"), id)
+	}
+	return &authorizationapi.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: id}}, nil
+}
+func (r *flappingClusterPolicyRegistry) CreateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r *flappingClusterPolicyRegistry) UpdateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r *flappingClusterPolicyRegistry) DeleteClusterPolicy(ctx apirequest.Context, id string) error {
+	return nil
+}
+
+// transientThenNotFoundClusterPolicyRegistry returns a transient (retryable) error from GetClusterPolicy for
+// the first transientCalls calls, then a genuine NotFound, for simulating a flaky etcd read that eventually
+// settles on "the cluster policy really doesn't exist yet."
+type transientThenNotFoundClusterPolicyRegistry struct {
+	transientCalls int
+	calls          int
+}
+
+func (r *transientThenNotFoundClusterPolicyRegistry) ListClusterPolicies(ctx apirequest.Context, options *metainternal.ListOptions) (*authorizationapi.ClusterPolicyList, error) {
+	return nil, nil
+}
+func (r *transientThenNotFoundClusterPolicyRegistry) GetClusterPolicy(ctx apirequest.Context, id string, options *metav1.GetOptions) (*authorizationapi.ClusterPolicy, error) {
+	r.calls++
+	if r.calls <= r.transientCalls {
+		return nil, kapierror.NewServerTimeout(authorizationapi.Resource("clusterpolicy"), "get", 0)
+	}
+	return nil, kapierror.NewNotFound(authorizationapi.Resource("clusterpolicy"), id)
+}
+func (r *transientThenNotFoundClusterPolicyRegistry) CreateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r *transientThenNotFoundClusterPolicyRegistry) UpdateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r *transientThenNotFoundClusterPolicyRegistry) DeleteClusterPolicy(ctx apirequest.Context, id string) error {
+	return nil
+}
+
+// recordingClusterPolicyRegistry counts UpdateClusterPolicy calls and returns policy from GetClusterPolicy,
+// for verifying that reapplyBootstrapPolicyFileIfChanged only writes back the checksum annotation when it
+// actually re-applies the bootstrap policy file.
+type recordingClusterPolicyRegistry struct {
+	policy      *authorizationapi.ClusterPolicy
+	updateCalls int
+}
+
+func (r *recordingClusterPolicyRegistry) ListClusterPolicies(ctx apirequest.Context, options *metainternal.ListOptions) (*authorizationapi.ClusterPolicyList, error) {
+	return nil, nil
+}
+func (r *recordingClusterPolicyRegistry) GetClusterPolicy(ctx apirequest.Context, id string, options *metav1.GetOptions) (*authorizationapi.ClusterPolicy, error) {
+	return r.policy, nil
+}
+func (r *recordingClusterPolicyRegistry) CreateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	return nil
+}
+func (r *recordingClusterPolicyRegistry) UpdateClusterPolicy(ctx apirequest.Context, policy *authorizationapi.ClusterPolicy) error {
+	r.updateCalls++
+	r.policy = policy
+	return nil
+}
+func (r *recordingClusterPolicyRegistry) DeleteClusterPolicy(ctx apirequest.Context, id string) error {
+	return nil
+}
+
+// TestConfirmClusterPolicyMissingDetectsFlappingGet verifies that confirmClusterPolicyMissing rechecks a
+// NotFound GetClusterPolicy result and reports the policy as present, not missing, when the recheck finds
+// it - guarding ReconcileComponentAuthorizationRules against triggering a destructive overwrite off a
+// transient Get.
+func TestConfirmClusterPolicyMissingDetectsFlappingGet(t *testing.T) {
+	registry := &flappingClusterPolicyRegistry{notFoundCalls: 1}
+	options := ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry:            registry,
+		ClusterPolicyMissingRecheckDelay: time.Millisecond,
+	}
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+
+	if confirmClusterPolicyMissing(options, reqCtx) {
+		t.Errorf("expected the recheck to find cluster policy present and report it as not missing")
+	}
+	if registry.calls != 1 {
+		t.Errorf("expected exactly one recheck Get, got %d calls", registry.calls)
+	}
+}
+
+// TestConfirmClusterPolicyMissingConfirmsGenuinelyMissing verifies that confirmClusterPolicyMissing still
+// reports the policy missing when the recheck also comes back NotFound.
+func TestConfirmClusterPolicyMissingConfirmsGenuinelyMissing(t *testing.T) {
+	registry := &flappingClusterPolicyRegistry{notFoundCalls: 2}
+	options := ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry:            registry,
+		ClusterPolicyMissingRecheckDelay: time.Millisecond,
+	}
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+
+	if !confirmClusterPolicyMissing(options, reqCtx) {
+		t.Errorf("expected the recheck to still find cluster policy missing")
+	}
+}
+
+// TestDetectAuthorizationModeLegacyOnly verifies that a cluster with only a legacy ClusterPolicy signal (no
+// rbac cluster role) is classified as AuthorizationModeLegacy.
+func TestDetectAuthorizationModeLegacyOnly(t *testing.T) {
+	if mode := detectAuthorizationMode(true, false); mode != AuthorizationModeLegacy {
+		t.Errorf("expected %v, got %v", AuthorizationModeLegacy, mode)
+	}
+}
+
+// TestDetectAuthorizationModeRBACOnly verifies that a cluster with only a native rbac cluster role signal (no
+// legacy ClusterPolicy) is classified as AuthorizationModeRBAC.
+func TestDetectAuthorizationModeRBACOnly(t *testing.T) {
+	if mode := detectAuthorizationMode(false, true); mode != AuthorizationModeRBAC {
+		t.Errorf("expected %v, got %v", AuthorizationModeRBAC, mode)
+	}
+}
+
+// TestDetectAuthorizationModeAmbiguous verifies that a cluster with both a legacy ClusterPolicy and a native
+// rbac cluster role signal - expected only mid-migration - is classified as AuthorizationModeAmbiguous rather
+// than guessing.
+func TestDetectAuthorizationModeAmbiguous(t *testing.T) {
+	if mode := detectAuthorizationMode(true, true); mode != AuthorizationModeAmbiguous {
+		t.Errorf("expected %v, got %v", AuthorizationModeAmbiguous, mode)
+	}
+}
+
+// TestDetectAuthorizationModeNeitherPresentDefaultsToLegacy verifies that a fresh cluster with neither signal
+// present is classified as AuthorizationModeLegacy, matching ReconcileComponentAuthorizationRules' existing
+// default behavior rather than treating "nothing yet" as ambiguous.
+func TestDetectAuthorizationModeNeitherPresentDefaultsToLegacy(t *testing.T) {
+	if mode := detectAuthorizationMode(false, false); mode != AuthorizationModeLegacy {
+		t.Errorf("expected %v, got %v", AuthorizationModeLegacy, mode)
+	}
+}
+
+// TestRBACClusterRolePresentDetectsExistingDiscoveryRole verifies that rbacClusterRolePresent reports true
+// when a native rbac ClusterRole for the discovery role already exists, false when it doesn't, and that a nil
+// getter (RBACClusterRoles left unset) reports false without error.
+func TestRBACClusterRolePresentDetectsExistingDiscoveryRole(t *testing.T) {
+	if present, err := rbacClusterRolePresent(nil); err != nil || present {
+		t.Errorf("expected a nil getter to report false with no error, got present=%v err=%v", present, err)
+	}
+
+	empty := fake.NewSimpleClientset()
+	if present, err := rbacClusterRolePresent(empty.Rbac()); err != nil || present {
+		t.Errorf("expected an empty clientset to report false with no error, got present=%v err=%v", present, err)
+	}
+
+	withDiscoveryRole := fake.NewSimpleClientset(&rbac.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: bootstrappolicy.DiscoveryRoleName},
+	})
+	if present, err := rbacClusterRolePresent(withDiscoveryRole.Rbac()); err != nil || !present {
+		t.Errorf("expected a clientset with the discovery role to report true, got present=%v err=%v", present, err)
+	}
+}
+
+// TestReconcileComponentAuthorizationRulesRefusesToRunWhenAuthorizationModeAmbiguous verifies that
+// ReconcileComponentAuthorizationRules records an error and does nothing else when both a legacy ClusterPolicy
+// and a native rbac cluster role are observed, rather than picking a reconcile path that could duplicate or
+// conflict policy.
+func TestReconcileComponentAuthorizationRulesRefusesToRunWhenAuthorizationModeAmbiguous(t *testing.T) {
+	registry := &recordingClusterPolicyRegistry{policy: &authorizationapi.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: authorizationapi.PolicyName}}}
+	rbacClientset := fake.NewSimpleClientset(&rbac.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: bootstrappolicy.DiscoveryRoleName},
+	})
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	ReconcileComponentAuthorizationRules(context.Background(), ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry: registry,
+		RBACClusterRoles:      rbacClientset.Rbac(),
+	}, result)
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected exactly one recorded error for the ambiguous mode, got %v", result.Errors)
+	}
+	if !strings.Contains(result.Errors[0].Error(), "ambiguous") {
+		t.Errorf("expected the recorded error to mention the ambiguous mode, got %v", result.Errors[0])
+	}
+}
+
+// TestGetClusterPolicyWithRetryRetriesTransientErrors verifies that getClusterPolicyWithRetry retries a
+// transient GetClusterPolicy error rather than immediately reporting the policy missing, and correctly
+// reports missing once the retried Get settles on a genuine NotFound.
+func TestGetClusterPolicyWithRetryRetriesTransientErrors(t *testing.T) {
+	registry := &transientThenNotFoundClusterPolicyRegistry{transientCalls: 2}
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+
+	policy, missing, err := getClusterPolicyWithRetry(reqCtx, registry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy != nil {
+		t.Errorf("expected no policy to be returned once the retried Get settles on NotFound, got %v", policy)
+	}
+	if !missing {
+		t.Errorf("expected the retried Get to report the cluster policy missing")
+	}
+	if registry.calls != 3 {
+		t.Errorf("expected getClusterPolicyWithRetry to retry through the transient errors to the NotFound, got %d calls", registry.calls)
+	}
+}
+
+// TestExpectedBootstrapClusterRoleNamesMatchesBootstrapPolicy verifies that ExpectedBootstrapClusterRoleNames
+// stays in sync with bootstrappolicy.GetBootstrapClusterRoles - the source of truth every reconciled and
+// created bootstrap cluster role is drawn from - and includes both the discovery role and a known controller
+// role, since it's meant to be diffable against a live cluster's `oc get clusterroles`.
+func TestExpectedBootstrapClusterRoleNamesMatchesBootstrapPolicy(t *testing.T) {
+	expected := sets.NewString()
+	for _, role := range bootstrappolicy.GetBootstrapClusterRoles() {
+		expected.Insert(role.Name)
+	}
+
+	got := sets.NewString(ExpectedBootstrapClusterRoleNames()...)
+
+	if !got.Equal(expected) {
+		t.Errorf("expected ExpectedBootstrapClusterRoleNames to match bootstrappolicy.GetBootstrapClusterRoles, missing=%v extra=%v",
+			expected.Difference(got).List(), got.Difference(expected).List())
+	}
+	if !got.Has(bootstrappolicy.DiscoveryRoleName) {
+		t.Errorf("expected the discovery role %q to be included", bootstrappolicy.DiscoveryRoleName)
+	}
+}
+
+func TestClusterPolicyRegistryLazyCachesInjectedRegistry(t *testing.T) {
+	fake := fakeClusterPolicyRegistry{}
+	c := &MasterConfig{clusterPolicyRegistry: fake}
+
+	first, err := c.clusterPolicyRegistryLazy()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := c.clusterPolicyRegistryLazy()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != fake || second != fake {
+		t.Fatalf("expected clusterPolicyRegistryLazy to keep returning the injected registry, not rebuild one")
+	}
+}
+
+func TestBootstrapResultStepCreatesOnFirstAccessAndReuses(t *testing.T) {
+	result := newBootstrapResult()
+
+	first := result.step("scc")
+	first.inc("created")
+	second := result.step("scc")
+	second.inc("created")
+
+	if first != second {
+		t.Fatalf("expected step(\"scc\") to return the same BootstrapStepResult on repeated calls")
+	}
+	if got := result.Steps["scc"].Counts["created"]; got != 2 {
+		t.Errorf("expected 2 created after two inc calls, got %d", got)
+	}
+}
+
+func TestBootstrapStepResultAddErrorIgnoresNil(t *testing.T) {
+	step := &BootstrapStepResult{Counts: map[string]int{}}
+
+	step.addError(nil)
+	if len(step.Errors) != 0 {
+		t.Fatalf("expected addError(nil) to be a no-op, got %v", step.Errors)
+	}
+
+	step.addError(errors.New("boom"))
+	if len(step.Errors) != 1 {
+		t.Fatalf("expected addError to record the non-nil error, got %v", step.Errors)
+	}
+}
+
+// fakeBootstrapLogger records every Info/Error call so tests can assert on the fields ensure* methods log.
+type fakeBootstrapLogger struct {
+	infos  []string
+	errors []string
+}
+
+func (f *fakeBootstrapLogger) Info(msg string, kv ...interface{}) {
+	f.infos = append(f.infos, msg+formatBootstrapLogFields(kv))
+}
+
+func (f *fakeBootstrapLogger) Error(err error, msg string, kv ...interface{}) {
+	f.errors = append(f.errors, msg+formatBootstrapLogFields(kv))
+}
+
+func TestFormatBootstrapLogFields(t *testing.T) {
+	got := formatBootstrapLogFields([]interface{}{"namespace", "openshift-infra", "role", "system:discovery"})
+	want := " namespace=openshift-infra role=system:discovery"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestEnsureNamespaceServiceAccountRoleBindingsLogsMissingBindingsWithNamespaceField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	log := &fakeBootstrapLogger{}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		BootstrapLog:                      log,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{Bootstrap: configapi.BootstrapOptions{VerifyServiceAccountRoleBindings: true}},
+		},
+	}
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+		},
+	}
+
+	c.ensureNamespaceServiceAccountRoleBindings(namespace)
+
+	found := false
+	for _, msg := range log.infos {
+		if strings.Contains(msg, "namespace=default") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a logged message carrying namespace=default, got %v", log.infos)
+	}
+}
+
+func TestEnsureNamespaceExistsStampsBootstrapLabelsOnCreate(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clientgotesting.GetAction).GetName()
+		return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), name)
+	})
+
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				BootstrapNamespaceLabels: map[string]string{"team": "platform"},
+			},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+	}
+
+	if _, err := c.ensureNamespaceExists(context.Background(), "openshift"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, action := range fakeKubeClient.Actions() {
+		if action.GetVerb() != "create" {
+			continue
+		}
+		ns := action.(clientgotesting.CreateAction).GetObject().(*kapi.Namespace)
+		if ns.Labels[bootstrapNamespaceLabel] != "true" {
+			t.Errorf("expected %s=true, got %v", bootstrapNamespaceLabel, ns.Labels)
+		}
+		if ns.Labels["team"] != "platform" {
+			t.Errorf("expected the configured BootstrapNamespaceLabels to be applied, got %v", ns.Labels)
+		}
+		if ns.Annotations[bootstrapMasterVersionAnnotation] == "" {
+			t.Errorf("expected %s to be stamped, got %v", bootstrapMasterVersionAnnotation, ns.Annotations)
+		}
+	}
+}
+
+// TestEnsureNamespaceExistsToleratesForbiddenCreateWhenConfigured verifies that a Forbidden error creating a
+// missing namespace is downgraded to informational, and reported as not created, when
+// TolerateNamespaceCreationForbidden is set.
+func TestEnsureNamespaceExistsToleratesForbiddenCreateWhenConfigured(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clientgotesting.GetAction).GetName()
+		return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), name)
+	})
+	fakeKubeClient.PrependReactor("create", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, kapierror.NewForbidden(kapi.Resource("namespaces"), "openshift", fmt.Errorf("not permitted"))
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{TolerateNamespaceCreationForbidden: true},
+			},
+		},
+	}
+
+	created, err := c.ensureNamespaceExists(context.Background(), "openshift")
+	if err != nil {
+		t.Fatalf("expected a tolerated Forbidden create to not be reported as an error, got: %v", err)
+	}
+	if created {
+		t.Errorf("expected created to be false when the namespace could not actually be created")
+	}
+}
+
+// TestEnsureNamespaceExistsFailsOnForbiddenCreateWhenNotConfigured verifies that a Forbidden error creating a
+// missing namespace is still treated as an error when TolerateNamespaceCreationForbidden is unset, preserving
+// the historical behavior.
+func TestEnsureNamespaceExistsFailsOnForbiddenCreateWhenNotConfigured(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clientgotesting.GetAction).GetName()
+		return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), name)
+	})
+	fakeKubeClient.PrependReactor("create", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, kapierror.NewForbidden(kapi.Resource("namespaces"), "openshift", fmt.Errorf("not permitted"))
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+	}
+
+	if _, err := c.ensureNamespaceExists(context.Background(), "openshift"); err == nil {
+		t.Fatal("expected a Forbidden create to be reported as an error when TolerateNamespaceCreationForbidden is unset")
+	}
+}
+
+// TestEnsureNamespaceExistsAppliesNamespaceMutatorOnCreate verifies that a configured NamespaceMutator's
+// modifications appear on the namespace object actually sent to Create.
+func TestEnsureNamespaceExistsAppliesNamespaceMutatorOnCreate(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clientgotesting.GetAction).GetName()
+		return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), name)
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		NamespaceMutator: func(namespace *kapi.Namespace) {
+			namespace.Finalizers = append(namespace.Finalizers, "example.com/custom-finalizer")
+			if namespace.Annotations == nil {
+				namespace.Annotations = map[string]string{}
+			}
+			namespace.Annotations["example.com/owner"] = "platform-team"
+		},
+	}
+
+	if _, err := c.ensureNamespaceExists(context.Background(), "openshift"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created, err := fakeKubeClient.Core().Namespaces().Get("openshift", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created.Finalizers) != 1 || created.Finalizers[0] != "example.com/custom-finalizer" {
+		t.Errorf("expected the mutator's finalizer to be set on the created namespace, got %v", created.Finalizers)
+	}
+	if created.Annotations["example.com/owner"] != "platform-team" {
+		t.Errorf("expected the mutator's annotation to be set on the created namespace, got %v", created.Annotations)
+	}
+}
+
+func TestEnsureNamespaceExistsPatchesLabelsOntoExistingNamespace(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset(&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift"}})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+	}
+
+	if _, err := c.ensureNamespaceExists(context.Background(), "openshift"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated := false
+	for _, action := range fakeKubeClient.Actions() {
+		if action.GetVerb() != "update" {
+			continue
+		}
+		ns := action.(clientgotesting.UpdateAction).GetObject().(*kapi.Namespace)
+		if ns.Labels[bootstrapNamespaceLabel] == "true" {
+			updated = true
+		}
+	}
+	if !updated {
+		t.Errorf("expected an already-existing namespace to be patched with the bootstrap label")
+	}
+}
+
+func TestEnsureNamespaceExistsAppliesRoleBindingsToPreExistingNamespace(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestCount++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	// The shared resources namespace already exists - created by a prior master version - and lacks the
+	// initialized-roles annotation, so its service account role bindings were never established.
+	fakeKubeClient := fake.NewSimpleClientset(&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift"}})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+	}
+
+	if _, err := c.ensureNamespaceExists(context.Background(), "openshift"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requestCount == 0 {
+		t.Errorf("expected ensureNamespaceExists to apply service account role bindings to a pre-existing namespace lacking the init annotation")
+	}
+}
+
+func TestReconcileNamespaceServiceAccountRoleBindingsBoundsConcurrency(t *testing.T) {
+	const namespaceCount = 20
+	const maxConcurrency = 3
+
+	var inFlight int32
+	var maxObserved int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			observed := atomic.LoadInt32(&maxObserved)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxObserved, observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{NamespaceInitConcurrency: maxConcurrency},
+			},
+		},
+	}
+
+	namespaces := make([]*kapi.Namespace, namespaceCount)
+	for i := range namespaces {
+		namespaces[i] = &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ns-%d", i)}}
+	}
+
+	c.ReconcileNamespaceServiceAccountRoleBindings(namespaces)
+
+	if got := atomic.LoadInt32(&maxObserved); got > maxConcurrency {
+		t.Errorf("expected at most %d namespaces to be processed concurrently, observed %d", maxConcurrency, got)
+	}
+	if got := atomic.LoadInt32(&maxObserved); got < maxConcurrency {
+		t.Errorf("expected concurrency to actually reach the configured limit of %d, observed %d", maxConcurrency, got)
+	}
+}
+
+func TestInfraNamespaceLabelsFallsBackToDefault(t *testing.T) {
+	c := &MasterConfig{}
+	labels := c.infraNamespaceLabels()
+	if labels["openshift.io/cluster-monitoring"] != "true" {
+		t.Errorf("expected the default monitoring label, got %v", labels)
+	}
+}
+
+func TestInfraNamespaceLabelsHonorsConfiguredOverride(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{InfraNamespaceLabels: map[string]string{"custom": "label"}},
+		},
+	}
+	labels := c.infraNamespaceLabels()
+	if labels["custom"] != "label" {
+		t.Errorf("expected the configured override to be used, got %v", labels)
+	}
+	if _, ok := labels["openshift.io/cluster-monitoring"]; ok {
+		t.Errorf("expected the configured override to replace, not merge with, the default, got %v", labels)
+	}
+}
+
+func TestBootstrapUpToDateWhenVersionAnnotationMatches(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: map[string]string{bootstrapMasterVersionAnnotation: version.Get().String()},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	if !c.bootstrapUpToDate() {
+		t.Errorf("expected bootstrapUpToDate to return true when the recorded version matches this binary")
+	}
+}
+
+func TestBootstrapUpToDateFalseWhenVersionAnnotationMismatches(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: map[string]string{bootstrapMasterVersionAnnotation: "some-other-version"},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	if c.bootstrapUpToDate() {
+		t.Errorf("expected bootstrapUpToDate to return false when the recorded version doesn't match this binary")
+	}
+}
+
+func TestBootstrapUpToDateFalseWhenNamespaceMissing(t *testing.T) {
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: "openshift-infra"},
+		},
+	}
+
+	if c.bootstrapUpToDate() {
+		t.Errorf("expected bootstrapUpToDate to return false when the infra namespace can't be read")
+	}
+}
+
+func TestBootstrapUpToDateFalseWhenForceFullBootstrapSet(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: map[string]string{bootstrapMasterVersionAnnotation: version.Get().String()},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		ForceFullBootstrap:                            true,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	if c.bootstrapUpToDate() {
+		t.Errorf("expected bootstrapUpToDate to return false when ForceFullBootstrap is set, even with a matching version annotation")
+	}
+}
+
+func TestRecordBootstrapVersionCompleteStampsCurrentVersion(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+	fakeKubeClient := fake.NewSimpleClientset(namespace)
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	c.recordBootstrapVersionComplete(context.Background())
+
+	current, err := fakeKubeClient.Core().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Annotations[bootstrapMasterVersionAnnotation] != version.Get().String() {
+		t.Fatalf("expected the completed bootstrap version to be recorded, got %v", current.Annotations)
+	}
+}
+
+// TestBootstrapStepAlreadyCompletedTrueForCompletedAndEarlierSteps verifies that a step recorded complete by
+// recordBootstrapStepComplete is reported as already completed for itself and every earlier step in
+// bootstrapStepOrder, since steps only ever run in that fixed order.
+func TestBootstrapStepAlreadyCompletedTrueForCompletedAndEarlierSteps(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ns,
+			Annotations: map[string]string{
+				bootstrapLastCompletedStepAnnotation:        configapi.BootstrapStepSharedResourcesNamespace,
+				bootstrapLastCompletedStepVersionAnnotation: version.Get().String(),
+			},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	for _, step := range []string{configapi.BootstrapStepComponentAuthz, configapi.BootstrapStepInfraNamespace, configapi.BootstrapStepSharedResourcesNamespace} {
+		if !c.bootstrapStepAlreadyCompleted(step) {
+			t.Errorf("expected step %q to be reported already completed", step)
+		}
+	}
+	for _, step := range []string{configapi.BootstrapStepDefaultNamespaceSARoles, configapi.BootstrapStepSCC} {
+		if c.bootstrapStepAlreadyCompleted(step) {
+			t.Errorf("expected step %q to be reported not yet completed", step)
+		}
+	}
+}
+
+// TestSkipAlreadyCompletedBootstrapStepFalseWhenForceFullBootstrapSet verifies that ForceFullBootstrap bypasses
+// the last-completed-step check unconditionally, the same way it bypasses bootstrapUpToDate.
+func TestSkipAlreadyCompletedBootstrapStepFalseWhenForceFullBootstrapSet(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ns,
+			Annotations: map[string]string{
+				bootstrapLastCompletedStepAnnotation:        configapi.BootstrapStepSCC,
+				bootstrapLastCompletedStepVersionAnnotation: version.Get().String(),
+			},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		ForceFullBootstrap:                            true,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	if c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepComponentAuthz, newBootstrapResult()) {
+		t.Errorf("expected skipAlreadyCompletedBootstrapStep to return false when ForceFullBootstrap is set")
+	}
+}
+
+// TestRecordBootstrapStepCompleteThenResumePastCompletedSteps simulates a failure partway through
+// EnsureBootstrapPolicy - component_authz completing before the process died - and a restart, verifying that
+// the restart's per-step checks resume at the step that never finished rather than redoing component_authz.
+func TestRecordBootstrapStepCompleteThenResumePastCompletedSteps(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+	fakeKubeClient := fake.NewSimpleClientset(namespace)
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	c.recordBootstrapStepComplete(configapi.BootstrapStepComponentAuthz)
+
+	result := newBootstrapResult()
+	if !c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepComponentAuthz, result) {
+		t.Errorf("expected the restart to skip the already-completed component_authz step")
+	}
+	if c.skipAlreadyCompletedBootstrapStep(configapi.BootstrapStepInfraNamespace, result) {
+		t.Errorf("expected the restart to resume at infra_namespace, the step that never completed")
+	}
+}
+
+// TestBootstrapStepAlreadyCompletedIgnoresRecordFromAnotherVersion verifies that a last-completed-step record
+// stamped by a different binary version than the one currently running is never treated as completed - the
+// scenario an upgrade hits when a prior version's clean run left "scc", the last entry in bootstrapStepOrder,
+// recorded on the infra namespace. Without this, bootstrapUpToDate correctly detects the version mismatch and
+// falls through to the step loop, but skipAlreadyCompletedBootstrapStep would then skip every single step
+// because "scc"'s index is always >= any step's index, silently defeating the version-based reconcile-on-
+// upgrade path and leaving any cluster roles, SCCs, or bindings the new version adds never created.
+func TestBootstrapStepAlreadyCompletedIgnoresRecordFromAnotherVersion(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ns,
+			Annotations: map[string]string{
+				bootstrapLastCompletedStepAnnotation:        configapi.BootstrapStepSCC,
+				bootstrapLastCompletedStepVersionAnnotation: "some-other-version-than-is-running",
+			},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	for _, step := range bootstrapStepOrder {
+		if c.bootstrapStepAlreadyCompleted(step) {
+			t.Errorf("expected step %q to be reported not completed, since the recorded completion is from a different version", step)
+		}
+		if c.skipAlreadyCompletedBootstrapStep(step, newBootstrapResult()) {
+			t.Errorf("expected step %q to run rather than be skipped, since the recorded completion is from a different version", step)
+		}
+	}
+}
+
+// TestRecordBootstrapStepCompleteReRunsEveryStepAfterAVersionUpgrade simulates a full, successful bootstrap
+// run under one version - stamping "scc", the last step in bootstrapStepOrder, complete - then a restart under
+// a different version, and verifies every step re-runs instead of every one being skipped past. It exercises
+// the same resume machinery TestRecordBootstrapStepCompleteThenResumePastCompletedSteps does, but across a
+// simulated version upgrade rather than a same-version restart.
+func TestRecordBootstrapStepCompleteReRunsEveryStepAfterAVersionUpgrade(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+	fakeKubeClient := fake.NewSimpleClientset(namespace)
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	c.recordBootstrapStepComplete(configapi.BootstrapStepSCC)
+
+	current, err := fakeKubeClient.Core().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Simulate an upgrade: a different binary version is now running than the one that stamped the record.
+	current.Annotations[bootstrapLastCompletedStepVersionAnnotation] = "some-other-version-than-is-running"
+	if _, err := fakeKubeClient.Core().Namespaces().Update(current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := newBootstrapResult()
+	for _, step := range bootstrapStepOrder {
+		if c.skipAlreadyCompletedBootstrapStep(step, result) {
+			t.Errorf("expected step %q to run after a version upgrade rather than be skipped", step)
+		}
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceReportsCreatedWhenNamespaceIsNew verifies that ensureOpenShiftInfraNamespace
+// returns created=true when the infra namespace didn't exist yet and this call created it.
+func TestEnsureOpenShiftInfraNamespaceReportsCreatedWhenNamespaceIsNew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	created, err := c.ensureOpenShiftInfraNamespace(context.Background(), result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Error("expected created to be true for a freshly created infra namespace")
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceReportsNotCreatedWhenNamespaceAlreadyExists verifies that
+// ensureOpenShiftInfraNamespace returns created=false when the infra namespace already existed.
+func TestEnsureOpenShiftInfraNamespaceReportsNotCreatedWhenNamespaceAlreadyExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	existing := &kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(existing),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	created, err := c.ensureOpenShiftInfraNamespace(context.Background(), result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Error("expected created to be false for an infra namespace that already existed")
+	}
+}
+
+func TestEnsureOpenShiftInfraNamespaceReturnsAggregateErrorForFailedRoleReconcile(t *testing.T) {
+	failingRole := bootstrappolicy.ControllerRoles()[0].Name
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/"+failingRole):
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"boom","reason":"InternalError","code":500}`)
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	_, err = c.ensureOpenShiftInfraNamespace(context.Background(), result)
+	if err == nil {
+		t.Fatalf("expected an aggregate error for the failed reconcile")
+	}
+	if !strings.Contains(err.Error(), "could not reconcile controller roles") {
+		t.Errorf("expected the aggregate error to name the reconcile that failed, got %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Errorf("expected the failure to also be recorded on the step result")
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceCapturesReconcileOutputInBootstrapOutput verifies that the controller role
+// and role binding reconciles route their human-readable "clusterrole/name created" style output through
+// MasterConfig.BootstrapOutput rather than always discarding it, so an operator debugging an upgrade can
+// capture exactly what a reconcile changed.
+func TestEnsureOpenShiftInfraNamespaceCapturesReconcileOutputInBootstrapOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	var out bytes.Buffer
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		BootstrapOutput: &out,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureOpenShiftInfraNamespace(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	firstControllerRole := bootstrappolicy.ControllerRoles()[0].Name
+	if !strings.Contains(out.String(), "clusterrole/"+firstControllerRole) {
+		t.Errorf("expected BootstrapOutput to capture the reconciled controller role, got %q", out.String())
+	}
+	if out.Len() == 0 {
+		t.Errorf("expected BootstrapOutput to capture some reconcile output, got none")
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceReportsForbiddenControllerRoleReconcile verifies that a role reconcile
+// failing because the loopback identity is forbidden from granting a controller role (escalation protection)
+// is distinguished from a generic reconcile failure: it's counted separately in the step result and its
+// error message calls out the permission problem rather than reporting a bare "could not reconcile" failure.
+func TestEnsureOpenShiftInfraNamespaceReportsForbiddenControllerRoleReconcile(t *testing.T) {
+	failingRole := bootstrappolicy.ControllerRoles()[0].Name
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/"+failingRole):
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"attempt to grant extra privileges","reason":"Forbidden","code":403}`)
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	_, err = c.ensureOpenShiftInfraNamespace(context.Background(), result)
+	if err == nil {
+		t.Fatalf("expected an aggregate error for the forbidden reconcile")
+	}
+	if !strings.Contains(err.Error(), "lacks permission to reconcile") {
+		t.Errorf("expected the error to call out the loopback identity's missing permissions, got %v", err)
+	}
+	if result.Counts["controllerRolesReconcileForbidden"] != 1 {
+		t.Errorf("expected the forbidden reconcile to be counted separately, got counts %v", result.Counts)
+	}
+	if len(result.Errors) == 0 {
+		t.Errorf("expected the failure to also be recorded on the step result")
+	}
+}
+
+// TestClassifyLoopbackAuthFailureEscalatesAfterThreshold simulates a loopback client that is uniformly
+// rejected as forbidden across an entire EnsureBootstrapPolicy run: once classifyLoopbackAuthFailure has seen
+// defaultLoopbackUnauthorizedThreshold such failures, it stops returning the raw reconcile error and instead
+// returns an *ErrLoopbackUnauthorized diagnosing the loopback client itself, so EnsureBootstrapPolicy can
+// short-circuit instead of reporting the same generic permission failure once per remaining step.
+func TestClassifyLoopbackAuthFailureEscalatesAfterThreshold(t *testing.T) {
+	c := &MasterConfig{}
+
+	for i := 1; i < defaultLoopbackUnauthorizedThreshold; i++ {
+		err := c.classifyLoopbackAuthFailure(kapierror.NewForbidden(schema.GroupResource{Resource: "clusterroles"}, "cluster-admin", nil))
+		if _, ok := err.(*ErrLoopbackUnauthorized); ok {
+			t.Fatalf("failure %d: did not expect escalation before the threshold of %d", i, defaultLoopbackUnauthorizedThreshold)
+		}
+	}
+
+	err := c.classifyLoopbackAuthFailure(kapierror.NewForbidden(schema.GroupResource{Resource: "clusterroles"}, "cluster-admin", nil))
+	loopbackErr, ok := err.(*ErrLoopbackUnauthorized)
+	if !ok {
+		t.Fatalf("expected an *ErrLoopbackUnauthorized once the threshold was reached, got %T: %v", err, err)
+	}
+	if !strings.Contains(loopbackErr.Error(), "credentials") {
+		t.Errorf("expected the error to mention checking credentials/RBAC, got %v", loopbackErr)
+	}
+	if loopbackErr.Cause() == nil {
+		t.Errorf("expected Cause() to return the underlying reconcile error")
+	}
+}
+
+// TestEnforceBootstrapStepFailurePolicyShortCircuitsOnLoopbackUnauthorized verifies that an
+// *ErrLoopbackUnauthorized recorded on a step's result is returned immediately by
+// enforceBootstrapStepFailurePolicy, even for a step whose StepFailurePolicy is Optional - a misconfigured
+// loopback client will keep failing every remaining step the same way, so there's no value in suppressing the
+// diagnosis just because this particular step is allowed to fail.
+func TestEnforceBootstrapStepFailurePolicyShortCircuitsOnLoopbackUnauthorized(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					StepFailurePolicies: map[string]configapi.BootstrapStepFailurePolicy{
+						BootstrapStepInfraNamespace: configapi.BootstrapStepOptional,
+					},
+				},
+			},
+		},
+	}
+
+	loopbackErr := &ErrLoopbackUnauthorized{Err: kapierror.NewForbidden(schema.GroupResource{Resource: "clusterroles"}, "cluster-admin", nil)}
+	result := &BootstrapStepResult{Counts: map[string]int{}, Errors: []error{loopbackErr}}
+
+	err := c.enforceBootstrapStepFailurePolicy(BootstrapStepInfraNamespace, result, nil)
+	if err != loopbackErr {
+		t.Fatalf("expected enforceBootstrapStepFailurePolicy to return the *ErrLoopbackUnauthorized unchanged, got %v", err)
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceRetriesTransientCreateFailures verifies that a transient failure creating
+// the infra namespace (a 503 here, standing in for a brief apiserver/etcd unavailability during startup) is
+// retried via retryOnTransientError rather than immediately given up on, so bootstrap doesn't leave the infra
+// namespace - and thus every controller's cluster permissions - unprovisioned until the next master restart.
+func TestEnsureOpenShiftInfraNamespaceRetriesTransientCreateFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	createAttempts := 0
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("create", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		createAttempts++
+		if createAttempts <= 2 {
+			return true, nil, kapierror.NewServiceUnavailable("apiserver temporarily unavailable")
+		}
+		return false, nil, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: "openshift-infra"},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureOpenShiftInfraNamespace(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if createAttempts != 3 {
+		t.Errorf("expected 2 transient failures followed by a successful create, got %d attempts", createAttempts)
+	}
+	if result.Counts["created"] != 1 {
+		t.Errorf("expected the namespace to be counted as created once retries succeeded, got counts %v", result.Counts)
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceWaitsOutTerminatingNamespace verifies that when the infra namespace
+// already exists but is caught mid-delete (Status.Phase == Terminating, likely left over from a very recent
+// `oc delete namespace` racing with this bootstrap run), ensureOpenShiftInfraNamespace polls rather than
+// charging straight into role reconciliation, and proceeds normally once the namespace reports Active.
+func TestEnsureOpenShiftInfraNamespaceWaitsOutTerminatingNamespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	getAttempts := 0
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("create", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, kapierror.NewAlreadyExists(kapi.Resource("namespaces"), "openshift-infra")
+	})
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		getAttempts++
+		phase := kapi.NamespaceActive
+		if getAttempts == 1 {
+			phase = kapi.NamespaceTerminating
+		}
+		return true, &kapi.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "openshift-infra"},
+			Status:     kapi.NamespaceStatus{Phase: phase},
+		}, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: "openshift-infra",
+				Bootstrap:                        configapi.BootstrapOptions{InfraNamespaceTerminatingWaitSeconds: 5},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureOpenShiftInfraNamespace(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if getAttempts < 2 {
+		t.Errorf("expected at least one poll past the initial Terminating Get, got %d Get attempts", getAttempts)
+	}
+}
+
+// TestWaitForInfraNamespaceActiveReturnsDescriptiveErrorWhenStuckTerminating verifies that a namespace which
+// never leaves the Terminating phase within the configured bound produces a clear error naming the namespace,
+// rather than an opaque poll timeout.
+func TestWaitForInfraNamespaceActiveReturnsDescriptiveErrorWhenStuckTerminating(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, &kapi.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "openshift-infra"},
+			Status:     kapi.NamespaceStatus{Phase: kapi.NamespaceTerminating},
+		}, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{InfraNamespaceTerminatingWaitSeconds: 1},
+			},
+		},
+	}
+
+	stuck := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-infra"},
+		Status:     kapi.NamespaceStatus{Phase: kapi.NamespaceTerminating},
+	}
+	_, err := c.waitForInfraNamespaceActive("openshift-infra", stuck)
+	if err == nil {
+		t.Fatal("expected an error for a namespace stuck Terminating")
+	}
+	if !strings.Contains(err.Error(), "openshift-infra") || !strings.Contains(err.Error(), "Terminating") {
+		t.Errorf("expected error to name the namespace and mention the Terminating phase, got: %v", err)
+	}
+}
+
+// TestDedupeControllerRoleNamesCollapsesDuplicatesAndReportsThem verifies that dedupeControllerRoleNames
+// collapses a duplicate role name down to a single entry - so it's reconciled exactly once - while reporting
+// the duplicate back to the caller to log as a warning.
+func TestDedupeControllerRoleNamesCollapsesDuplicatesAndReportsThem(t *testing.T) {
+	roles := []rbac.ClusterRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "system:build-controller"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "system:deployer-controller"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "system:build-controller"}},
+	}
+
+	names, duplicates := dedupeControllerRoleNames(roles)
+
+	if want := []string{"system:build-controller", "system:deployer-controller"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected the duplicate to be reconciled once, got %v", names)
+	}
+	if want := []string{"system:build-controller"}; !reflect.DeepEqual(duplicates, want) {
+		t.Errorf("expected the duplicate role name to be reported, got %v", duplicates)
+	}
+
+	log := &fakeBootstrapLogger{}
+	c := &MasterConfig{BootstrapLog: log}
+	if len(duplicates) > 0 {
+		c.bootstrapLog().Info("Duplicate controller role names in bootstrappolicy.ControllerRoles; reconciling each once", "duplicates", duplicates)
+	}
+	found := false
+	for _, msg := range log.infos {
+		if strings.Contains(msg, "Duplicate controller role names") && strings.Contains(msg, "system:build-controller") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a warning naming the duplicate role, got %v", log.infos)
+	}
+}
+
+// TestEnsureOpenShiftInfraNamespaceReconcilesControllerRolesInASingleCall verifies that reconciling the
+// controller roles and role bindings issues one RunReconcileClusterRoles/RunReconcileClusterRoleBindings
+// call covering every role, rather than one call per role as the old parallelize-based loop did. It counts
+// the GET requests each reconcile issues against the individual cluster roles/bindings: RunReconcile*
+// fetches every named role/binding exactly once per call, so N GETs for N roles across a single test run
+// confirms one call was made, not N.
+func TestEnsureOpenShiftInfraNamespaceReconcilesControllerRolesInASingleCall(t *testing.T) {
+	controllerRoles := bootstrappolicy.ControllerRoles()
+	controllerRoleBindings := bootstrappolicy.ControllerRoleBindings()
+
+	seenRoleGets := sets.NewString()
+	seenRoleBindingGets := sets.NewString()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/clusterroles/"):
+			seenRoleGets.Insert(path.Base(req.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/clusterrolebindings/"):
+			seenRoleBindingGets.Insert(path.Base(req.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureOpenShiftInfraNamespace(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(seenRoleGets) != len(controllerRoles) {
+		t.Errorf("expected exactly one reconcile pass covering all %d controller roles, saw GETs for %d: %v", len(controllerRoles), len(seenRoleGets), seenRoleGets.List())
+	}
+	for _, role := range controllerRoles {
+		if !seenRoleGets.Has(role.Name) {
+			t.Errorf("expected the single reconcile call to include role %q", role.Name)
+		}
+	}
+
+	if len(seenRoleBindingGets) != len(controllerRoleBindings) {
+		t.Errorf("expected exactly one reconcile pass covering all %d controller role bindings, saw GETs for %d: %v", len(controllerRoleBindings), len(seenRoleBindingGets), seenRoleBindingGets.List())
+	}
+	for _, roleBinding := range controllerRoleBindings {
+		if !seenRoleBindingGets.Has(roleBinding.RoleRef.Name) {
+			t.Errorf("expected the single reconcile call to include role binding %q", roleBinding.RoleRef.Name)
+		}
+	}
+
+	if result.Counts["controllerRolesReconciled"] != len(controllerRoles) {
+		t.Errorf("expected controllerRolesReconciled to be %d, got %d", len(controllerRoles), result.Counts["controllerRolesReconciled"])
+	}
+	if result.Counts["controllerRoleBindingsReconciled"] != len(controllerRoleBindings) {
+		t.Errorf("expected controllerRoleBindingsReconciled to be %d, got %d", len(controllerRoleBindings), result.Counts["controllerRoleBindingsReconciled"])
+	}
+}
+
+func TestEnsureOpenShiftInfraNamespaceReconcilesAdditionalControllerRoleBindings(t *testing.T) {
+	knownRole := bootstrappolicy.ControllerRoles()[0].Name
+	const bindingName = "extra-controller-binding"
+
+	var created *authorizationapi.ClusterRoleBinding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/clusterrolebindings/"+bindingName):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodPost && strings.Contains(req.URL.Path, "/clusterrolebindings"):
+			body, _ := ioutil.ReadAll(req.Body)
+			created = &authorizationapi.ClusterRoleBinding{}
+			json.Unmarshal(body, created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	subject := kapi.ObjectReference{Kind: "ServiceAccount", Namespace: ns, Name: "extra-controller"}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				AdditionalControllerRoleBindings: []configapi.AdditionalControllerRoleBinding{
+					{Name: bindingName, RoleName: knownRole, Subjects: []kapi.ObjectReference{subject}},
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureOpenShiftInfraNamespace(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if created == nil {
+		t.Fatalf("expected the additional controller role binding to be created")
+	}
+	if created.RoleRef.Name != knownRole {
+		t.Errorf("expected roleRef %q, got %q", knownRole, created.RoleRef.Name)
+	}
+	if len(created.Subjects) != 1 || created.Subjects[0] != subject {
+		t.Errorf("expected subjects %v, got %v", []kapi.ObjectReference{subject}, created.Subjects)
+	}
+	if result.Counts["additionalControllerRoleBindingsReconciled"] != 1 {
+		t.Errorf("expected additionalControllerRoleBindingsReconciled to be 1, got %d", result.Counts["additionalControllerRoleBindingsReconciled"])
+	}
+}
+
+func TestEnsureOpenShiftInfraNamespaceSkipsAdditionalControllerRoleBindingWithUnknownRole(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && (strings.Contains(req.URL.Path, "clusterroles") || strings.Contains(req.URL.Path, "clusterrolebindings")):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		default:
+			t.Fatalf("expected no write requests for an unknown-role binding, got %s %s", req.Method, req.URL.Path)
+			return
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				AdditionalControllerRoleBindings: []configapi.AdditionalControllerRoleBinding{
+					{Name: "extra-controller-binding", RoleName: "does-not-exist", Subjects: []kapi.ObjectReference{{Kind: "ServiceAccount", Name: "extra-controller"}}},
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureOpenShiftInfraNamespace(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Counts["additionalControllerRoleBindingsReconciled"] != 0 {
+		t.Errorf("expected the unknown-role binding not to be reconciled, got count %d", result.Counts["additionalControllerRoleBindingsReconciled"])
+	}
+	if len(result.Errors) == 0 {
+		t.Errorf("expected the unknown-role binding to be recorded as an error")
+	}
+}
+
+func TestEnsureInfraNamespaceQuotaAndLimitRangeCreatesWhenConfigured(t *testing.T) {
+	ns := "openshift-infra"
+	fakeKubeClient := fake.NewSimpleClientset()
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				InfraNamespaceQuota: &kapi.ResourceQuotaSpec{
+					Hard: kapi.ResourceList{kapi.ResourcePods: resource.MustParse("10")},
+				},
+				InfraNamespaceLimitRange: &kapi.LimitRangeSpec{
+					Limits: []kapi.LimitRangeItem{{Type: kapi.LimitTypeContainer}},
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if err := c.ensureInfraNamespaceQuota(ns, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.ensureInfraNamespaceLimitRange(ns, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	quota, err := fakeKubeClient.Core().ResourceQuotas(ns).Get(infraNamespaceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the infra resource quota to be created: %v", err)
+	}
+	if quota.Spec.Hard.Pods().String() != "10" {
+		t.Errorf("expected the configured hard pod limit to be applied, got %v", quota.Spec.Hard)
+	}
+
+	if _, err := fakeKubeClient.Core().LimitRanges(ns).Get(infraNamespaceLimitRangeName, metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected the infra limit range to be created: %v", err)
+	}
+
+	if result.Counts["created"] != 2 {
+		t.Errorf("expected 2 created counts, got %v", result.Counts)
+	}
+}
+
+func TestEnsureInfraNamespaceQuotaAndLimitRangeNoopWhenUnset(t *testing.T) {
+	ns := "openshift-infra"
+	fakeKubeClient := fake.NewSimpleClientset()
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if err := c.ensureInfraNamespaceQuota(ns, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.ensureInfraNamespaceLimitRange(ns, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := fakeKubeClient.Core().ResourceQuotas(ns).Get(infraNamespaceQuotaName, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no resource quota to be created when InfraNamespaceQuota is unset")
+	}
+	if _, err := fakeKubeClient.Core().LimitRanges(ns).Get(infraNamespaceLimitRangeName, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no limit range to be created when InfraNamespaceLimitRange is unset")
+	}
+	if len(result.Counts) != 0 {
+		t.Errorf("expected no counts to be recorded, got %v", result.Counts)
+	}
+}
+
+func TestEnsureDefaultSecurityContextConstraintsSkipsAlreadyExistingSCCWhenReconcileDisabled(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	existingName := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)[0].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{
+		existingName: {ObjectMeta: metav1.ObjectMeta{Name: existingName}},
+	}}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				Bootstrap: configapi.BootstrapOptions{
+					ReconcileSecurityContextConstraints:  false,
+					PruneStaleSecurityContextConstraints: false,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Counts["skipped"] != 1 {
+		t.Errorf("expected the already-existing SCC to be counted as skipped, got counts %v", result.Counts)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no errors, got %v", result.Errors)
+	}
+}
+
+func TestEnsureDefaultSecurityContextConstraintsReportsCreatedVsExistingNames(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	if len(bootstrapSCCs) < 2 {
+		t.Fatalf("expected at least two bootstrap SCCs to exercise this test, got %d", len(bootstrapSCCs))
+	}
+	existingName := bootstrapSCCs[0].Name
+	createdName := bootstrapSCCs[1].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{
+		existingName: {ObjectMeta: metav1.ObjectMeta{Name: existingName}},
+	}}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := sets.NewString(result.Names["created"]...)
+	existing := sets.NewString(result.Names["existing"]...)
+	if !created.Has(createdName) {
+		t.Errorf("expected %q to be reported as created, got %v", createdName, result.Names["created"])
+	}
+	if !existing.Has(existingName) {
+		t.Errorf("expected %q to be reported as existing, got %v", existingName, result.Names["existing"])
+	}
+	if created.Has(existingName) || existing.Has(createdName) {
+		t.Errorf("expected created and existing to be disjoint, got created=%v existing=%v", result.Names["created"], result.Names["existing"])
+	}
+}
+
+// TestEnsureDefaultSecurityContextConstraintsReturnsCreatedCount verifies that the returned created count
+// matches the number of bootstrap SCCs that weren't already present, not merely the total bootstrap set.
+func TestEnsureDefaultSecurityContextConstraintsReturnsCreatedCount(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	if len(bootstrapSCCs) < 2 {
+		t.Fatalf("expected at least two bootstrap SCCs to exercise this test, got %d", len(bootstrapSCCs))
+	}
+	existingName := bootstrapSCCs[0].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{
+		existingName: {ObjectMeta: metav1.ObjectMeta{Name: existingName}},
+	}}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	created, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantCreated := len(bootstrapSCCs) - 1
+	if created != wantCreated {
+		t.Errorf("expected %d SCCs to be created (all but the one pre-existing), got %d", wantCreated, created)
+	}
+}
+
+// TestEnsureDefaultSecurityContextConstraintsAugmentsWithLabeledServiceAccounts verifies that, when
+// InfraSCCServiceAccountLabelSelector is set, a labeled infra service account is unioned into the privileged
+// SCC's user list on top of the static bootstrap set, while an unlabeled service account in the same
+// namespace is left out.
+func TestEnsureDefaultSecurityContextConstraintsAugmentsWithLabeledServiceAccounts(t *testing.T) {
+	ns := "openshift-infra"
+
+	labeledSA := &kapi.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "labeled-controller", Namespace: ns, Labels: map[string]string{"scc-access": "privileged"}},
+	}
+	unlabeledSA := &kapi.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "unlabeled-controller", Namespace: ns},
+	}
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(labeledSA, unlabeledSA),
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace:    ns,
+				InfraSCCServiceAccountLabelSelector: "scc-access=privileged",
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	privileged := sccClient.sccs[bootstrappolicy.SecurityContextConstraintPrivileged]
+	if privileged == nil {
+		t.Fatalf("expected the privileged SCC to have been created")
+	}
+	users := sets.NewString(privileged.Users...)
+	labeledUsername := serviceaccount.MakeUsername(ns, labeledSA.Name)
+	unlabeledUsername := serviceaccount.MakeUsername(ns, unlabeledSA.Name)
+	if !users.Has(labeledUsername) {
+		t.Errorf("expected labeled service account %q to be granted privileged SCC access, got users %v", labeledUsername, users.List())
+	}
+	if users.Has(unlabeledUsername) {
+		t.Errorf("expected unlabeled service account %q not to be granted privileged SCC access, got users %v", unlabeledUsername, users.List())
+	}
+}
+
+// TestEnsureDefaultSecurityContextConstraintsAugmentsGroupsOnReconcile verifies that, when SCCGroupLabelSelector
+// is set, a label-selected group is unioned into the configured SCC's group list on reconcile, while the SCC's
+// existing static groups are preserved.
+func TestEnsureDefaultSecurityContextConstraintsAugmentsGroupsOnReconcile(t *testing.T) {
+	ns := "openshift-infra"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"GroupList","apiVersion":"v1","items":[{"metadata":{"name":"dynamic-admins","labels":{"scc-access":"privileged"}}}]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	staticGroups, _ := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	privilegedStaticGroups := staticGroups[bootstrappolicy.SecurityContextConstraintPrivileged]
+	if len(privilegedStaticGroups) == 0 {
+		t.Fatalf("expected the privileged SCC to have at least one static bootstrap group to exercise preservation")
+	}
+
+	existing := &securityapi.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{Name: bootstrappolicy.SecurityContextConstraintPrivileged, Labels: map[string]string{bootstrapOwnedSCCLabel: "true"}},
+		Groups:     append([]string{}, privilegedStaticGroups...),
+	}
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{existing.Name: existing}}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		SCCClient:                         sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				SCCGroupLabelSelector:            "scc-access=privileged",
+				Bootstrap: configapi.BootstrapOptions{
+					ReconcileSecurityContextConstraints: true,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reconciled := sccClient.sccs[bootstrappolicy.SecurityContextConstraintPrivileged]
+	groups := sets.NewString(reconciled.Groups...)
+	if !groups.Has("dynamic-admins") {
+		t.Errorf("expected the label-selected group to be unioned into the SCC's groups, got %v", reconciled.Groups)
+	}
+	for _, staticGroup := range privilegedStaticGroups {
+		if !groups.Has(staticGroup) {
+			t.Errorf("expected static bootstrap group %q to be preserved, got %v", staticGroup, reconciled.Groups)
+		}
+	}
+}
+
+func TestEnsureDefaultSecurityContextConstraintsSkipsAdminSuppressedSCC(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	if len(bootstrapSCCs) < 2 {
+		t.Fatalf("expected at least two bootstrap SCCs to exercise suppression, got %d", len(bootstrapSCCs))
+	}
+	suppressedName := bootstrapSCCs[0].Name
+	otherName := bootstrapSCCs[1].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	fakeKubeClient := fake.NewSimpleClientset(&kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: map[string]string{suppressedSCCsAnnotation: suppressedName},
+		},
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, exists := sccClient.sccs[suppressedName]; exists {
+		t.Errorf("expected suppressed SCC %q not to be created", suppressedName)
+	}
+	if _, exists := sccClient.sccs[otherName]; !exists {
+		t.Errorf("expected non-suppressed SCC %q to still be created", otherName)
+	}
+	if result.Counts["suppressed"] != 1 {
+		t.Errorf("expected exactly one SCC to be counted as suppressed, got counts %v", result.Counts)
+	}
+}
+
+func TestSuppressedBootstrapSCCNamesEmptyWhenNamespaceMissing(t *testing.T) {
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: "openshift-infra"},
+		},
+	}
+
+	if suppressed := c.suppressedBootstrapSCCNames(); suppressed.Len() != 0 {
+		t.Errorf("expected no suppressed SCCs when the infra namespace doesn't exist yet, got %v", suppressed.List())
+	}
+}
+
+func TestInfraNamespaceHonorsEnvOverride(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: "openshift-infra"},
+		},
+	}
+
+	if got := c.infraNamespace(); got != "openshift-infra" {
+		t.Errorf("expected the configured namespace when no override is set, got %q", got)
+	}
+
+	os.Setenv(infraNamespaceEnvVar, "openshift-infra-test-1")
+	defer os.Unsetenv(infraNamespaceEnvVar)
+
+	if got := c.infraNamespace(); got != "openshift-infra-test-1" {
+		t.Errorf("expected %s to override the configured namespace, got %q", infraNamespaceEnvVar, got)
+	}
+}
+
+func TestEnsureDefaultSecurityContextConstraintsUsesEnvOverriddenNamespaceForSCCAccess(t *testing.T) {
+	configuredNS := "openshift-infra"
+	overriddenNS := "openshift-infra-test-2"
+
+	os.Setenv(infraNamespaceEnvVar, overriddenNS)
+	defer os.Unsetenv(infraNamespaceEnvVar)
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: configuredNS},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	overriddenGroups, overriddenUsers := bootstrappolicy.GetBoostrapSCCAccess(overriddenNS)
+	expectedSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(overriddenGroups, overriddenUsers)
+	privilegedName := bootstrappolicy.SecurityContextConstraintPrivileged
+
+	var expectedPrivileged, createdPrivileged *securityapi.SecurityContextConstraints
+	for i := range expectedSCCs {
+		if expectedSCCs[i].Name == privilegedName {
+			expectedPrivileged = &expectedSCCs[i]
+		}
+	}
+	createdPrivileged = sccClient.sccs[privilegedName]
+	if expectedPrivileged == nil || createdPrivileged == nil {
+		t.Fatalf("expected the privileged SCC to be computed and created, got expected=%v created=%v", expectedPrivileged, createdPrivileged)
+	}
+
+	for _, user := range expectedPrivileged.Users {
+		found := false
+		for _, createdUser := range createdPrivileged.Users {
+			if createdUser == user {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected privileged SCC users to include %q (computed from the env-overridden namespace %q), got %v", user, overriddenNS, createdPrivileged.Users)
+		}
+	}
+	for _, user := range createdPrivileged.Users {
+		if strings.Contains(user, configuredNS) {
+			t.Errorf("expected no SCC user to be computed from the unused configured namespace %q, got %v", configuredNS, createdPrivileged.Users)
+		}
+	}
+}
+
+// TestEnsureDefaultSecurityContextConstraintsVerifySCCServiceAccountReferencesWarnsOnMissingSA verifies that,
+// with VerifySCCServiceAccountReferences enabled, a bootstrap SCC user referencing a service account that
+// doesn't exist is logged as a warning and counted, without creating the missing service account.
+func TestEnsureDefaultSecurityContextConstraintsVerifySCCServiceAccountReferencesWarnsOnMissingSA(t *testing.T) {
+	ns := "openshift-infra"
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	fakeKubeClient := fake.NewSimpleClientset()
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				Bootstrap: configapi.BootstrapOptions{
+					VerifySCCServiceAccountReferences: true,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Counts["sccServiceAccountMissing"] == 0 {
+		t.Errorf("expected the missing build-controller service account reference to be counted, got counts %v", result.Counts)
+	}
+	if _, err := fakeKubeClient.Core().ServiceAccounts(ns).Get(bootstrappolicy.InfraBuildControllerServiceAccountName, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the missing service account not to be created when CreateMissingSCCServiceAccounts is false")
+	}
+}
+
+// TestEnsureDefaultSecurityContextConstraintsVerifySCCServiceAccountReferencesSkipsExistingSA verifies that a
+// bootstrap SCC user referencing a service account that already exists is not counted as missing.
+func TestEnsureDefaultSecurityContextConstraintsVerifySCCServiceAccountReferencesSkipsExistingSA(t *testing.T) {
+	ns := "openshift-infra"
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	existingSA := &kapi.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: bootstrappolicy.InfraBuildControllerServiceAccountName}}
+	fakeKubeClient := fake.NewSimpleClientset(existingSA)
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				Bootstrap: configapi.BootstrapOptions{
+					VerifySCCServiceAccountReferences: true,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Counts["sccServiceAccountMissing"] != 0 {
+		t.Errorf("expected no missing service account references when the referenced SA already exists, got counts %v", result.Counts)
+	}
+}
+
+// TestEnsureDefaultSecurityContextConstraintsCreatesMissingSCCServiceAccountWhenAllowed verifies that, with
+// both VerifySCCServiceAccountReferences and CreateMissingSCCServiceAccounts enabled, a missing service
+// account referenced by a bootstrap SCC is created rather than only logged.
+func TestEnsureDefaultSecurityContextConstraintsCreatesMissingSCCServiceAccountWhenAllowed(t *testing.T) {
+	ns := "openshift-infra"
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	fakeKubeClient := fake.NewSimpleClientset()
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				Bootstrap: configapi.BootstrapOptions{
+					VerifySCCServiceAccountReferences: true,
+					CreateMissingSCCServiceAccounts:   true,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	if _, err := c.ensureDefaultSecurityContextConstraints(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Counts["sccServiceAccountCreated"] == 0 {
+		t.Errorf("expected the missing build-controller service account reference to be created, got counts %v", result.Counts)
+	}
+	if _, err := fakeKubeClient.Core().ServiceAccounts(ns).Get(bootstrappolicy.InfraBuildControllerServiceAccountName, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the missing service account to have been created, got error: %v", err)
+	}
+}
+
+func TestBootstrapCompleteReflectsSetBootstrapComplete(t *testing.T) {
+	c := &MasterConfig{}
+
+	if c.BootstrapComplete() {
+		t.Fatalf("expected BootstrapComplete to be false before setBootstrapComplete is called")
+	}
+
+	c.setBootstrapComplete()
+
+	if !c.BootstrapComplete() {
+		t.Fatalf("expected BootstrapComplete to be true after setBootstrapComplete is called")
+	}
+}
+
+func TestBootstrapCompleteHandlerRespectsFlag(t *testing.T) {
+	c := &MasterConfig{}
+	handler := c.BootstrapCompleteHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d before bootstrap completes, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+
+	c.setBootstrapComplete()
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected %d after bootstrap completes, got %d", http.StatusOK, recorder.Code)
+	}
+}
+
+func TestEnsureBootstrapPolicyLeavesBootstrapCompleteFalseOnRequiredStepFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"ClusterRoleList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		clusterPolicyRegistry:                         fakeClusterPolicyRegistry{},
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: "openshift-infra"},
+		},
+	}
+
+	if _, err := c.EnsureBootstrapPolicy(ctx); err == nil {
+		t.Fatalf("expected EnsureBootstrapPolicy to return an error for an already-cancelled context")
+	}
+	if c.BootstrapComplete() {
+		t.Errorf("expected BootstrapComplete to stay false when a required step fails")
+	}
+}
+
+func TestRegisterPostBootstrapHookRunsRegisteredHooksInOrderOnSuccess(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: map[string]string{bootstrapMasterVersionAnnotation: version.Get().String()},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns, OpenShiftSharedResourcesNamespace: "openshift"},
+		},
+	}
+
+	var ran []string
+	c.RegisterPostBootstrapHook("first", func(ctx context.Context) error {
+		ran = append(ran, "first")
+		return nil
+	})
+	c.RegisterPostBootstrapHook("second", func(ctx context.Context) error {
+		ran = append(ran, "second")
+		return errors.New("second failed")
+	})
+	c.RegisterPostBootstrapHook("third", func(ctx context.Context) error {
+		ran = append(ran, "third")
+		return nil
+	})
+
+	result, err := c.EnsureBootstrapPolicy(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"first", "second", "third"}) {
+		t.Errorf("expected hooks to run in registration order regardless of a middle hook's error, got %v", ran)
+	}
+
+	step := result.Steps["post_bootstrap_hooks"]
+	if step == nil {
+		t.Fatalf("expected a post_bootstrap_hooks step to be recorded")
+	}
+	if step.Counts["succeeded"] != 2 {
+		t.Errorf("expected 2 hooks to be recorded as succeeded, got %d", step.Counts["succeeded"])
+	}
+	if len(step.Errors) != 1 {
+		t.Errorf("expected the failing hook's error to be recorded, got %v", step.Errors)
+	}
+}
+
+func TestEnsureBootstrapPolicyRejectsConcurrentCalls(t *testing.T) {
+	ns := "openshift-infra"
+	namespace := &kapi.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: map[string]string{bootstrapMasterVersionAnnotation: version.Get().String()},
+		},
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(namespace),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: ns, OpenShiftSharedResourcesNamespace: "openshift"},
+		},
+	}
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	c.RegisterPostBootstrapHook("block-until-released", func(ctx context.Context) error {
+		close(entered)
+		<-release
+		return nil
+	})
+
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := c.EnsureBootstrapPolicy(context.Background())
+		firstErr <- err
+	}()
+
+	<-entered
+
+	if _, err := c.EnsureBootstrapPolicy(context.Background()); err != ErrBootstrapAlreadyRunning {
+		t.Errorf("expected a concurrent call to be rejected with ErrBootstrapAlreadyRunning, got %v", err)
+	}
+
+	close(release)
+
+	if err := <-firstErr; err != nil {
+		t.Errorf("expected the first call to succeed, got %v", err)
+	}
+	if _, err := c.EnsureBootstrapPolicy(context.Background()); err != nil {
+		t.Errorf("expected a call made after the first finished to succeed, got %v", err)
+	}
+}
+
+func TestRegisterPostBootstrapHookSkippedWhenRequiredStepFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"kind":"ClusterRoleList","apiVersion":"v1","items":[]}`)
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient:             osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		clusterPolicyRegistry:                         fakeClusterPolicyRegistry{},
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{OpenShiftInfrastructureNamespace: "openshift-infra"},
+		},
+	}
+
+	hookRan := false
+	c.RegisterPostBootstrapHook("should-not-run", func(ctx context.Context) error {
+		hookRan = true
+		return nil
+	})
+
+	if _, err := c.EnsureBootstrapPolicy(ctx); err == nil {
+		t.Fatalf("expected EnsureBootstrapPolicy to return an error for an already-cancelled context")
+	}
+	if hookRan {
+		t.Errorf("expected the post-bootstrap hook not to run when a required step fails")
+	}
+}
+
+func TestOverwriteAndReconcileNamespacedBootstrapRolesFlagCombinations(t *testing.T) {
+	trueVal, falseVal := true, false
+
+	tests := []struct {
+		name                   string
+		overwriteFlag          *bool
+		reconcileFlag          *bool
+		clusterPolicyMissing   bool
+		expectOverwrite        bool
+		expectReconcileNsRoles bool
+	}{
+		{
+			name:                   "both unset preserves historical combined behavior when missing",
+			overwriteFlag:          nil,
+			reconcileFlag:          nil,
+			clusterPolicyMissing:   true,
+			expectOverwrite:        true,
+			expectReconcileNsRoles: true,
+		},
+		{
+			name:                   "both unset preserves historical combined behavior when found",
+			overwriteFlag:          nil,
+			reconcileFlag:          nil,
+			clusterPolicyMissing:   false,
+			expectOverwrite:        false,
+			expectReconcileNsRoles: false,
+		},
+		{
+			name:                   "disaster recovery: never overwrite but always reconcile even when found",
+			overwriteFlag:          &falseVal,
+			reconcileFlag:          &trueVal,
+			clusterPolicyMissing:   false,
+			expectOverwrite:        false,
+			expectReconcileNsRoles: true,
+		},
+		{
+			name:                   "explicit false disables both regardless of missing",
+			overwriteFlag:          &falseVal,
+			reconcileFlag:          &falseVal,
+			clusterPolicyMissing:   true,
+			expectOverwrite:        false,
+			expectReconcileNsRoles: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &MasterConfig{
+				Options: configapi.MasterConfig{
+					PolicyConfig: configapi.PolicyConfig{
+						Bootstrap: configapi.BootstrapOptions{
+							OverwriteBootstrapPolicyIfMissing: test.overwriteFlag,
+							ReconcileNamespacedBootstrapRoles: test.reconcileFlag,
+						},
+					},
+				},
+			}
+
+			if got := test.clusterPolicyMissing && c.overwriteBootstrapPolicyIfMissing(); got != test.expectOverwrite {
+				t.Errorf("expected overwrite=%v, got %v", test.expectOverwrite, got)
+			}
+			if got := c.reconcileNamespacedBootstrapRoles(test.clusterPolicyMissing); got != test.expectReconcileNsRoles {
+				t.Errorf("expected reconcileNamespacedBootstrapRoles=%v, got %v", test.expectReconcileNsRoles, got)
+			}
+		})
+	}
+}
+
+type fakeBootstrapAuditor struct {
+	records []BootstrapAuditRecord
+}
+
+func (f *fakeBootstrapAuditor) Record(record BootstrapAuditRecord) {
+	f.records = append(f.records, record)
+}
+
+func TestAuditBootstrapMutationRecordsActorActionAndObject(t *testing.T) {
+	auditor := &fakeBootstrapAuditor{}
+	c := &MasterConfig{BootstrapAuditor: auditor}
+
+	c.auditBootstrapMutation("namespace-create", "openshift-infra")
+
+	if len(auditor.records) != 1 {
+		t.Fatalf("expected exactly one audit record, got %d", len(auditor.records))
+	}
+	record := auditor.records[0]
+	if record.Actor != bootstrapAuditActor {
+		t.Errorf("expected actor %q, got %q", bootstrapAuditActor, record.Actor)
+	}
+	if record.Action != "namespace-create" {
+		t.Errorf("expected action %q, got %q", "namespace-create", record.Action)
+	}
+	if record.Object != "openshift-infra" {
+		t.Errorf("expected object %q, got %q", "openshift-infra", record.Object)
+	}
+	if record.Timestamp.IsZero() {
+		t.Errorf("expected a non-zero timestamp")
+	}
+}
+
+func TestAuditBootstrapMutationDefaultsToNoopAuditor(t *testing.T) {
+	c := &MasterConfig{}
+
+	// Must not panic when no BootstrapAuditor is configured.
+	c.auditBootstrapMutation("namespace-create", "openshift-infra")
+}
+
+func TestEnsureNamespaceExistsAuditsNamespaceCreate(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("get", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clientgotesting.GetAction).GetName()
+		return true, nil, kapierror.NewNotFound(kapi.Resource("namespaces"), name)
+	})
+	auditor := &fakeBootstrapAuditor{}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		BootstrapAuditor: auditor,
+	}
+
+	if _, err := c.ensureNamespaceExists(context.Background(), "openshift"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(auditor.records) != 1 {
+		t.Fatalf("expected exactly one audit record for namespace creation, got %d", len(auditor.records))
+	}
+	if auditor.records[0].Action != "namespace-create" || auditor.records[0].Object != "openshift" {
+		t.Errorf("unexpected audit record: %+v", auditor.records[0])
+	}
+}
+
+func TestServiceAccountRoleRetryBackoffDefaultsToJitteredBackoff(t *testing.T) {
+	c := &MasterConfig{}
+
+	backoff := c.serviceAccountRoleRetryBackoff()
+
+	if backoff.Jitter <= 0 {
+		t.Errorf("expected the default backoff to include a jitter component, got %v", backoff.Jitter)
+	}
+}
+
+func TestServiceAccountRoleRetryBackoffHonorsConfiguredJitter(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					ServiceAccountRoleRetryBackoff: configapi.BootstrapRetryBackoff{
+						InitialIntervalSeconds: 1,
+						Factor:                 2,
+						Steps:                  3,
+						Jitter:                 0.5,
+					},
+				},
+			},
+		},
+	}
+
+	backoff := c.serviceAccountRoleRetryBackoff()
+
+	if backoff.Jitter != 0.5 {
+		t.Errorf("expected configured jitter 0.5, got %v", backoff.Jitter)
+	}
+	if backoff.Steps != 3 {
+		t.Errorf("expected configured steps 3, got %v", backoff.Steps)
+	}
+}
+
+func TestNextDefaultNamespaceWaitIntervalGrowsAndCaps(t *testing.T) {
+	interval := DefaultNamespaceWaitInterval
+	seen := []time.Duration{interval}
+	for i := 0; i < 6; i++ {
+		interval = nextDefaultNamespaceWaitInterval(interval)
+		seen = append(seen, interval)
+	}
+
+	expected := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		DefaultNamespaceWaitMaxInterval,
+		DefaultNamespaceWaitMaxInterval,
+		DefaultNamespaceWaitMaxInterval,
+	}
+	if !reflect.DeepEqual(seen, expected) {
+		t.Errorf("expected backoff sequence %v, got %v", expected, seen)
+	}
+}
+
+func TestReconcileSecurityContextConstraintsWithNoNamesReconcilesAll(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	if len(bootstrapSCCs) < 2 {
+		t.Fatalf("expected at least two bootstrap SCCs to exercise this test, got %d", len(bootstrapSCCs))
+	}
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	if err := c.ReconcileSecurityContextConstraints(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sccClient.sccs) != len(bootstrapSCCs) {
+		t.Errorf("expected all %d bootstrap SCCs to be created, got %d", len(bootstrapSCCs), len(sccClient.sccs))
+	}
+	for _, scc := range bootstrapSCCs {
+		if _, ok := sccClient.sccs[scc.Name]; !ok {
+			t.Errorf("expected bootstrap SCC %q to have been created", scc.Name)
+		}
+	}
+}
+
+func TestReconcileSecurityContextConstraintsWithNamesReconcilesOnlyThoseNames(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	if len(bootstrapSCCs) < 2 {
+		t.Fatalf("expected at least two bootstrap SCCs to exercise this test, got %d", len(bootstrapSCCs))
+	}
+	targetName := bootstrapSCCs[0].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	if err := c.ReconcileSecurityContextConstraints(targetName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sccClient.sccs) != 1 {
+		t.Errorf("expected only the named SCC to be created, got %v", sccClient.sccs)
+	}
+	if _, ok := sccClient.sccs[targetName]; !ok {
+		t.Errorf("expected %q to have been created", targetName)
+	}
+}
+
+func TestReconcileSecurityContextConstraintsRejectsUnknownName(t *testing.T) {
+	ns := "openshift-infra"
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	err := c.ReconcileSecurityContextConstraints("not-a-real-scc")
+	if err == nil {
+		t.Fatal("expected an error for an unknown SCC name")
+	}
+	if !strings.Contains(err.Error(), "not-a-real-scc") {
+		t.Errorf("expected the error to name the unknown SCC, got %v", err)
+	}
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	for _, scc := range bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users) {
+		if !strings.Contains(err.Error(), scc.Name) {
+			t.Errorf("expected the error to list valid SCC name %q, got %v", scc.Name, err)
+		}
+	}
+	if len(sccClient.sccs) != 0 {
+		t.Errorf("expected no SCCs to be created when given an unknown name, got %v", sccClient.sccs)
+	}
+}
+
+// TestReconcileSecurityContextConstraintsAppliesPriorityOverride verifies that a
+// SecurityContextConstraintPriorityOverrides entry is written to the target SCC's Priority field on create,
+// letting an operator make a bootstrap SCC win or lose priority over others without editing its embedded
+// definition.
+func TestReconcileSecurityContextConstraintsAppliesPriorityOverride(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	targetName := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)[0].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	override := int32(20)
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace:           ns,
+				SecurityContextConstraintPriorityOverrides: map[string]*int32{targetName: &override},
+			},
+		},
+	}
+
+	if err := c.ReconcileSecurityContextConstraints(targetName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created, ok := sccClient.sccs[targetName]
+	if !ok {
+		t.Fatalf("expected %q to be created", targetName)
+	}
+	if created.Priority == nil || *created.Priority != override {
+		t.Errorf("expected priority override %d to be written, got %v", override, created.Priority)
+	}
+}
+
+// TestReconcileSecurityContextConstraintsRejectsUnknownPriorityOverrideName verifies that an override naming
+// an SCC outside the bootstrap set is rejected up front, rather than being silently ignored.
+func TestReconcileSecurityContextConstraintsRejectsUnknownPriorityOverrideName(t *testing.T) {
+	ns := "openshift-infra"
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	override := int32(20)
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace:           ns,
+				SecurityContextConstraintPriorityOverrides: map[string]*int32{"not-a-real-scc": &override},
+			},
+		},
+	}
+
+	err := c.ReconcileSecurityContextConstraints()
+	if err == nil {
+		t.Fatal("expected an error for an unknown SCC name in the priority overrides")
+	}
+	if !strings.Contains(err.Error(), "not-a-real-scc") {
+		t.Errorf("expected the error to name the unknown SCC, got %v", err)
+	}
+	if len(sccClient.sccs) != 0 {
+		t.Errorf("expected no SCCs to be created when a priority override name is unknown, got %v", sccClient.sccs)
+	}
+}
+
+func TestPlanSecurityContextConstraintReconcileMarksMissingSCCAsCreate(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	targetName := bootstrapSCCs[0].Name
+
+	sccClient := &fakeSCCClient{sccs: map[string]*securityapi.SecurityContextConstraints{}}
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	plan, err := c.PlanSecurityContextConstraintReconcile(targetName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Entries) != 1 || plan.Entries[0].Action != SCCReconcileActionCreate {
+		t.Fatalf("expected a single create entry for %q, got %+v", targetName, plan.Entries)
+	}
+	if len(sccClient.sccs) != 0 {
+		t.Errorf("expected planning to never create anything, got %v", sccClient.sccs)
+	}
+}
+
+func TestPlanSecurityContextConstraintReconcileMarksMatchingSCCAsNone(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	target := bootstrapSCCs[0]
+
+	existing := target
+	sccClient := newFakeSCCClient(&existing)
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	plan, err := c.PlanSecurityContextConstraintReconcile(target.Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Entries) != 1 || plan.Entries[0].Action != SCCReconcileActionNone {
+		t.Fatalf("expected a single no-op entry for %q, got %+v", target.Name, plan.Entries)
+	}
+	if sccClient.updated != nil {
+		t.Errorf("expected planning to never call Update, but Update was called with %+v", sccClient.updated)
+	}
+}
+
+func TestPlanSecurityContextConstraintReconcileMarksDriftedSCCAsUpdate(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	target := bootstrapSCCs[0]
+
+	driftedPriority := int32(123456)
+	drifted := target
+	drifted.Priority = &driftedPriority
+	sccClient := newFakeSCCClient(&drifted)
+	c := &MasterConfig{
+		SCCClient: sccClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	plan, err := c.PlanSecurityContextConstraintReconcile(target.Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Entries) != 1 || plan.Entries[0].Action != SCCReconcileActionUpdate {
+		t.Fatalf("expected a single update entry for %q, got %+v", target.Name, plan.Entries)
+	}
+	found := false
+	for _, field := range plan.Entries[0].ChangedFields {
+		if field == "priority" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected priority to be listed as a changed field, got %v", plan.Entries[0].ChangedFields)
+	}
+	if sccClient.updated != nil {
+		t.Errorf("expected planning to never call Update, but Update was called with %+v", sccClient.updated)
+	}
+}
+
+func TestSecurityContextConstraintsStatusHealthyWhenAllMatch(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+
+	sccs := map[string]*securityapi.SecurityContextConstraints{}
+	for i := range bootstrapSCCs {
+		scc := bootstrapSCCs[i]
+		sccs[scc.Name] = &scc
+	}
+	c := &MasterConfig{
+		SCCClient: &fakeSCCClient{sccs: sccs},
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	status, err := c.SecurityContextConstraintsStatus()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != SCCHealthStatusHealthy || len(status.Missing) != 0 || len(status.Drifted) != 0 {
+		t.Fatalf("expected a healthy status with no missing or drifted SCCs, got %+v", status)
+	}
+}
+
+func TestSecurityContextConstraintsStatusReportsMissingSCC(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	missingName := bootstrapSCCs[0].Name
+
+	sccs := map[string]*securityapi.SecurityContextConstraints{}
+	for i := range bootstrapSCCs {
+		scc := bootstrapSCCs[i]
+		if scc.Name == missingName {
+			continue
+		}
+		sccs[scc.Name] = &scc
+	}
+	c := &MasterConfig{
+		SCCClient: &fakeSCCClient{sccs: sccs},
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	status, err := c.SecurityContextConstraintsStatus()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != SCCHealthStatusDegraded {
+		t.Fatalf("expected a degraded status, got %+v", status)
+	}
+	if len(status.Missing) != 1 || status.Missing[0] != missingName {
+		t.Fatalf("expected %q to be reported missing, got %+v", missingName, status.Missing)
+	}
+	if len(status.Drifted) != 0 {
+		t.Errorf("expected no drifted SCCs, got %v", status.Drifted)
+	}
+}
+
+func TestSecurityContextConstraintsStatusReportsDriftedSCC(t *testing.T) {
+	ns := "openshift-infra"
+	groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+	bootstrapSCCs := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)
+	driftedName := bootstrapSCCs[0].Name
+
+	driftedPriority := int32(123456)
+	sccs := map[string]*securityapi.SecurityContextConstraints{}
+	for i := range bootstrapSCCs {
+		scc := bootstrapSCCs[i]
+		if scc.Name == driftedName {
+			scc.Priority = &driftedPriority
+		}
+		sccs[scc.Name] = &scc
+	}
+	c := &MasterConfig{
+		SCCClient: &fakeSCCClient{sccs: sccs},
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	status, err := c.SecurityContextConstraintsStatus()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != SCCHealthStatusDegraded {
+		t.Fatalf("expected a degraded status, got %+v", status)
+	}
+	if len(status.Drifted) != 1 || status.Drifted[0] != driftedName {
+		t.Fatalf("expected %q to be reported drifted, got %+v", driftedName, status.Drifted)
+	}
+	if len(status.Missing) != 0 {
+		t.Errorf("expected no missing SCCs, got %v", status.Missing)
+	}
+}
+
+// TestReapplyBootstrapPolicyFileIfChangedSkipsWhenChecksumMatches verifies that when the bootstrap policy
+// file's checksum matches bootstrapPolicyChecksumAnnotation already recorded on the cluster policy,
+// reapplyBootstrapPolicyFileIfChanged does nothing: no re-apply attempt is logged and the registry is never
+// updated.
+func TestReapplyBootstrapPolicyFileIfChangedSkipsWhenChecksumMatches(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "bootstrap-policy")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(`{"kind":"Template","apiVersion":"v1","objects":[]}`); err != nil {
+		t.Fatalf("unexpected error writing temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	checksum, err := bootstrapPolicyFileChecksum(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("unexpected error computing checksum: %v", err)
+	}
+
+	existingPolicy := &authorizationapi.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        authorizationapi.PolicyName,
+			Annotations: map[string]string{bootstrapPolicyChecksumAnnotation: checksum},
+		},
+	}
+	registry := &recordingClusterPolicyRegistry{policy: existingPolicy}
+	log := &fakeBootstrapLogger{}
+	options := ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry: registry,
+		BootstrapPolicyFile:   tmpFile.Name(),
+		Log:                   log,
+	}
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+
+	reapplyBootstrapPolicyFileIfChanged(reqCtx, options, existingPolicy, func(eventtype, reason, messageFmt string, args ...interface{}) {})
+
+	if registry.updateCalls != 0 {
+		t.Errorf("expected no update calls when the file is unchanged, got %d", registry.updateCalls)
+	}
+	for _, msg := range log.infos {
+		if strings.Contains(msg, "re-applying") {
+			t.Errorf("expected no reapply attempt to be logged when the file is unchanged, got %v", log.infos)
+		}
+	}
+}
+
+// TestReapplyBootstrapPolicyFileIfChangedTriggersWhenChecksumDiffers verifies that a bootstrap policy file
+// whose checksum no longer matches the recorded annotation triggers a re-apply attempt, distinguishing it
+// from the unchanged case above.
+func TestReapplyBootstrapPolicyFileIfChangedTriggersWhenChecksumDiffers(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "bootstrap-policy")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(`{"kind":"Template","apiVersion":"v1","objects":[`); err != nil {
+		t.Fatalf("unexpected error writing temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	existingPolicy := &authorizationapi.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        authorizationapi.PolicyName,
+			Annotations: map[string]string{bootstrapPolicyChecksumAnnotation: "stale-checksum"},
+		},
+	}
+	registry := &recordingClusterPolicyRegistry{policy: existingPolicy}
+	log := &fakeBootstrapLogger{}
+	options := ComponentAuthorizationRulesOptions{
+		ClusterPolicyRegistry: registry,
+		BootstrapPolicyFile:   tmpFile.Name(),
+		Log:                   log,
+	}
+	reqCtx := apirequest.WithNamespace(apirequest.NewContext(), "")
+
+	reapplyBootstrapPolicyFileIfChanged(reqCtx, options, existingPolicy, func(eventtype, reason, messageFmt string, args ...interface{}) {})
+
+	found := false
+	for _, msg := range log.infos {
+		if strings.Contains(msg, "re-applying") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a reapply attempt to be logged when the checksum differs, got %v", log.infos)
+	}
+	if registry.updateCalls != 0 {
+		t.Errorf("expected no checksum update since the malformed file fails validation before reaching the registry, got %d", registry.updateCalls)
+	}
+}
+
+func TestEnforceBootstrapStepFailurePolicyAbortsOnRequiredStepFailure(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					StepFailurePolicies: map[string]configapi.BootstrapStepFailurePolicy{
+						"example_step": configapi.BootstrapStepRequired,
+					},
+				},
+			},
+		},
+	}
+	stepErr := errors.New("boom")
+	stepResult := &BootstrapStepResult{Counts: map[string]int{}}
+
+	if err := c.enforceBootstrapStepFailurePolicy("example_step", stepResult, stepErr); err != stepErr {
+		t.Fatalf("expected a Required step's own error to be returned unchanged, got %v", err)
+	}
+
+	// A Required step can also fail by recording errors on its result without returning one itself, the way
+	// the void ensure* steps do; enforceBootstrapStepFailurePolicy must catch that case too.
+	stepResult = &BootstrapStepResult{Counts: map[string]int{}}
+	stepResult.addError(errors.New("recorded failure"))
+	if err := c.enforceBootstrapStepFailurePolicy("example_step", stepResult, nil); err == nil {
+		t.Fatalf("expected a Required step's recorded errors to abort even with a nil stepErr")
+	}
+}
+
+func TestEnforceBootstrapStepFailurePolicyContinuesOnOptionalStepFailure(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					StepFailurePolicies: map[string]configapi.BootstrapStepFailurePolicy{
+						"example_step": configapi.BootstrapStepOptional,
+					},
+				},
+			},
+		},
+	}
+	stepResult := &BootstrapStepResult{Counts: map[string]int{}}
+	stepResult.addError(errors.New("boom"))
+
+	if err := c.enforceBootstrapStepFailurePolicy("example_step", stepResult, nil); err != nil {
+		t.Fatalf("expected an Optional step's recorded failure not to abort, got %v", err)
+	}
+	if len(stepResult.Errors) != 1 {
+		t.Errorf("expected the Optional step's failure to remain recorded on its result, got %v", stepResult.Errors)
+	}
+}
+
+// fakeRoleBindingAccessor is a minimal policy.RoleBindingAccessor for exercising
+// missingServiceAccountRoleBindings without a real apiserver.
+type fakeRoleBindingAccessor struct {
+	existingByRole map[string][]*authorizationapi.RoleBinding
+}
+
+func (a fakeRoleBindingAccessor) GetExistingRoleBindingsForRole(roleNamespace, role string) ([]*authorizationapi.RoleBinding, error) {
+	return a.existingByRole[role], nil
+}
+
+func (a fakeRoleBindingAccessor) GetExistingRoleBindingNames() (*sets.String, error) {
+	return &sets.String{}, nil
+}
+
+func (a fakeRoleBindingAccessor) UpdateRoleBinding(binding *authorizationapi.RoleBinding) error {
+	return nil
+}
+
+func (a fakeRoleBindingAccessor) CreateRoleBinding(binding *authorizationapi.RoleBinding) error {
+	return nil
+}
+
+func TestMissingServiceAccountRoleBindingsReportsOnlyIncompleteRoles(t *testing.T) {
+	builder := kapi.ObjectReference{Kind: authorizationapi.ServiceAccountKind, Name: "builder"}
+	deployer := kapi.ObjectReference{Kind: authorizationapi.ServiceAccountKind, Name: "deployer"}
+	desired := []authorizationapi.RoleBinding{
+		{RoleRef: kapi.ObjectReference{Name: "system:image-builder"}, Subjects: []kapi.ObjectReference{builder}},
+		{RoleRef: kapi.ObjectReference{Name: "system:deployer"}, Subjects: []kapi.ObjectReference{deployer}},
+	}
+	accessor := fakeRoleBindingAccessor{existingByRole: map[string][]*authorizationapi.RoleBinding{
+		"system:image-builder": {{Subjects: []kapi.ObjectReference{builder}}},
+	}}
+
+	missing, err := missingServiceAccountRoleBindings(accessor, desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"system:deployer"}) {
+		t.Errorf("expected only system:deployer to be reported missing, got %v", missing)
+	}
+}
+
+func TestMissingServiceAccountRoleBindingsReportsNoneWhenComplete(t *testing.T) {
+	puller := kapi.ObjectReference{Kind: authorizationapi.SystemGroupKind, Name: "system:serviceaccounts:myproject"}
+	desired := []authorizationapi.RoleBinding{
+		{RoleRef: kapi.ObjectReference{Name: "system:image-puller"}, Subjects: []kapi.ObjectReference{puller}},
+	}
+	accessor := fakeRoleBindingAccessor{existingByRole: map[string][]*authorizationapi.RoleBinding{
+		"system:image-puller": {{Subjects: []kapi.ObjectReference{puller}}},
+	}}
+
+	missing, err := missingServiceAccountRoleBindings(accessor, desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing role bindings, got %v", missing)
+	}
+}
+
+func TestVerifyAllProjectServiceAccountRoleBindingsReportsOnlyIncompleteNamespaces(t *testing.T) {
+	// "complete" already has all three bootstrap project role bindings bound; "incomplete" is missing the
+	// image-builder and deployer bindings. The handler serves a canned RoleBindingList per namespace, keyed off
+	// the request path, the way the rest of this file's httptest-server tests do.
+	completeList := `{"kind":"RoleBindingList","apiVersion":"v1","items":[
+		{"metadata":{"name":"system:image-pullers","namespace":"complete"},"roleRef":{"name":"system:image-puller"},"subjects":[{"kind":"SystemGroup","name":"system:serviceaccounts:complete"}]},
+		{"metadata":{"name":"system:image-builders","namespace":"complete"},"roleRef":{"name":"system:image-builder"},"subjects":[{"kind":"ServiceAccount","name":"builder"}]},
+		{"metadata":{"name":"system:deployers","namespace":"complete"},"roleRef":{"name":"system:deployer"},"subjects":[{"kind":"ServiceAccount","name":"deployer"}]}
+	]}`
+	incompleteList := `{"kind":"RoleBindingList","apiVersion":"v1","items":[
+		{"metadata":{"name":"system:image-pullers","namespace":"incomplete"},"roleRef":{"name":"system:image-puller"},"subjects":[{"kind":"SystemGroup","name":"system:serviceaccounts:incomplete"}]}
+	]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		switch {
+		case strings.Contains(req.URL.Path, "/namespaces/complete/"):
+			fmt.Fprint(w, completeList)
+		case strings.Contains(req.URL.Path, "/namespaces/incomplete/"):
+			fmt.Fprint(w, incompleteList)
+		default:
+			fmt.Fprint(w, `{"kind":"RoleBindingList","apiVersion":"v1","items":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "complete"}},
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "incomplete"}},
+		),
+	}
+
+	results, err := c.VerifyAllProjectServiceAccountRoleBindings()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected only the incomplete namespace to be reported, got %#v", results)
+	}
+	if results[0].Namespace != "incomplete" {
+		t.Errorf("expected the incomplete namespace to be reported, got %q", results[0].Namespace)
+	}
+	expectedMissing := sets.NewString("system:image-builder", "system:deployer")
+	if actual := sets.NewString(results[0].MissingRoleBindings...); !actual.Equal(expectedMissing) {
+		t.Errorf("expected missing role bindings %v, got %v", expectedMissing.List(), actual.List())
+	}
+}
+
+func TestListUninitializedServiceAccountRoleNamespacesReportsOnlyUnannotated(t *testing.T) {
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "initialized",
+				Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+			}},
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "uninitialized"}},
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "stale",
+				Annotations: map[string]string{"openshift.io/sa.initialized-roles": "false"},
+			}},
+		),
+	}
+
+	uninitialized, err := c.ListUninitializedServiceAccountRoleNamespaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := sets.NewString("uninitialized", "stale")
+	if actual := sets.NewString(uninitialized...); !actual.Equal(expected) {
+		t.Errorf("expected uninitialized namespaces %v, got %v", expected.List(), actual.List())
+	}
+}
+
+func TestListUninitializedServiceAccountRoleNamespacesHonorsCustomAnnotation(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{ServiceAccountRolesInitializedAnnotation: "example.com/sa.initialized-roles"},
+		},
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "initialized",
+				Annotations: map[string]string{"example.com/sa.initialized-roles": "true"},
+			}},
+			&kapi.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "wrong-annotation",
+				Annotations: map[string]string{"openshift.io/sa.initialized-roles": "true"},
+			}},
+		),
+	}
+
+	uninitialized, err := c.ListUninitializedServiceAccountRoleNamespaces()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(uninitialized, []string{"wrong-annotation"}) {
+		t.Errorf("expected only wrong-annotation to be reported, got %v", uninitialized)
+	}
+}
+
+// recordedSpan is one span captured by a recordingBootstrapTracer, including every tag SetTag was called
+// with and whether Finish was called.
+type recordedSpan struct {
+	name     string
+	tags     map[string]interface{}
+	finished bool
+}
+
+// recordingBootstrapTracer is an in-memory BootstrapTracer that records every span it starts, for asserting
+// on tracing behavior without a real OpenTracing/OpenTelemetry backend.
+type recordingBootstrapTracer struct {
+	spans []*recordedSpan
+}
+
+func (r *recordingBootstrapTracer) StartSpan(name string) BootstrapSpan {
+	span := &recordedSpan{name: name, tags: map[string]interface{}{}}
+	r.spans = append(r.spans, span)
+	return span
+}
+
+func (s *recordedSpan) SetTag(key string, value interface{}) { s.tags[key] = value }
+func (s *recordedSpan) Finish()                              { s.finished = true }
+
+func TestTraceBootstrapStepRecordsOneFinishedSpanPerStep(t *testing.T) {
+	tracer := &recordingBootstrapTracer{}
+	c := &MasterConfig{BootstrapTracer: tracer}
+
+	steps := []string{
+		configapi.BootstrapStepComponentAuthz,
+		configapi.BootstrapStepInfraNamespace,
+		configapi.BootstrapStepSharedResourcesNamespace,
+		configapi.BootstrapStepDefaultNamespaceSARoles,
+		configapi.BootstrapStepSCC,
+	}
+	for _, step := range steps {
+		var err error
+		if step == configapi.BootstrapStepSCC {
+			err = errors.New("boom")
+		}
+		if traceErr := c.traceBootstrapStep(step, map[string]interface{}{"namespace": "myproject"}, func() error { return err }); traceErr != err {
+			t.Fatalf("expected traceBootstrapStep to return fn's error unchanged for step %s, got %v", step, traceErr)
+		}
+	}
+
+	if len(tracer.spans) != len(steps) {
+		t.Fatalf("expected exactly one span per step, got %d spans for %d steps", len(tracer.spans), len(steps))
+	}
+	for i, span := range tracer.spans {
+		if !span.finished {
+			t.Errorf("expected span for step %s to be finished", steps[i])
+		}
+		if span.tags["step"] != steps[i] {
+			t.Errorf("expected span for step %s to be tagged with its step name, got %v", steps[i], span.tags["step"])
+		}
+		if span.tags["namespace"] != "myproject" {
+			t.Errorf("expected span for step %s to carry the namespace tag, got %v", steps[i], span.tags["namespace"])
+		}
+		wantError := steps[i] == configapi.BootstrapStepSCC
+		if _, hasError := span.tags["error"]; hasError != wantError {
+			t.Errorf("expected span for step %s to have an error tag set only on failure, got %v", steps[i], span.tags)
+		}
+	}
+}
+
+func TestBootstrapTracerDefaultsToNoop(t *testing.T) {
+	c := &MasterConfig{}
+	span := c.bootstrapTracer().StartSpan("some_step")
+	span.SetTag("step", "some_step")
+	span.Finish()
+}
+
+func TestDetectOrphanedBootstrapRoleBindingsReportsBindingsWithMissingRole(t *testing.T) {
+	bindings := bootstrappolicy.GetBootstrapClusterRoleBindings()
+	missingRole := bindings[0].RoleRef.Name
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/clusterroles/"+missingRole) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"kind":"ClusterRole","apiVersion":"v1","metadata":{"name":%q}}`, path.Base(req.URL.Path))
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+	c := &MasterConfig{PrivilegedLoopbackOpenShiftClient: osClient}
+
+	orphans, err := c.DetectOrphanedBootstrapRoleBindings()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantNames := sets.NewString()
+	for _, binding := range bindings {
+		if binding.RoleRef.Name == missingRole {
+			wantNames.Insert(binding.Name)
+		}
+	}
+	if len(orphans) != wantNames.Len() {
+		t.Fatalf("expected %d orphans (one per binding referencing %q), got %d: %+v", wantNames.Len(), missingRole, len(orphans), orphans)
+	}
+	for _, orphan := range orphans {
+		if !wantNames.Has(orphan.Name) {
+			t.Errorf("unexpected orphan %+v", orphan)
+		}
+		if orphan.MissingRoleRef != missingRole {
+			t.Errorf("expected MissingRoleRef %q, got %q", missingRole, orphan.MissingRoleRef)
+		}
+	}
+}
+
+func TestDetectOrphanedBootstrapRoleBindingsReportsNoneWhenAllRolesExist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"kind":"ClusterRole","apiVersion":"v1","metadata":{"name":%q}}`, path.Base(req.URL.Path))
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+	c := &MasterConfig{PrivilegedLoopbackOpenShiftClient: osClient}
+
+	orphans, err := c.DetectOrphanedBootstrapRoleBindings()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("expected no orphans, got %+v", orphans)
+	}
+}
+
+func TestVerifyBootstrapRoleBindingIntegrityPrunesOrphansWhenConfigured(t *testing.T) {
+	bindings := bootstrappolicy.GetBootstrapClusterRoleBindings()
+	missingRole := bindings[0].RoleRef.Name
+	orphanName := bindings[0].Name
+
+	deleted := sets.NewString()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/clusterroles/"+missingRole):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/clusterroles/"):
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"kind":"ClusterRole","apiVersion":"v1","metadata":{"name":%q}}`, path.Base(req.URL.Path))
+		case req.Method == http.MethodDelete && strings.Contains(req.URL.Path, "/clusterrolebindings/"):
+			deleted.Insert(path.Base(req.URL.Path))
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Success"}`)
+		default:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					VerifyBootstrapRoleBindingIntegrity: true,
+					PruneOrphanedBootstrapRoleBindings:  true,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	c.verifyBootstrapRoleBindingIntegrity(result)
+
+	if !deleted.Has(orphanName) {
+		t.Errorf("expected orphaned binding %q to be deleted, deleted: %v", orphanName, deleted.List())
+	}
+	if result.Counts["orphanedRoleBindingsDetected"] == 0 {
+		t.Errorf("expected orphanedRoleBindingsDetected to be recorded")
+	}
+	if result.Counts["orphanedRoleBindingsPruned"] == 0 {
+		t.Errorf("expected orphanedRoleBindingsPruned to be recorded")
+	}
+}
+
+func TestVerifyBootstrapRoleBindingIntegrityDoesNotDeleteWithoutPruneFlag(t *testing.T) {
+	bindings := bootstrappolicy.GetBootstrapClusterRoleBindings()
+	missingRole := bindings[0].RoleRef.Name
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/clusterroles/"+missingRole):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		case req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/clusterroles/"):
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"kind":"ClusterRole","apiVersion":"v1","metadata":{"name":%q}}`, path.Base(req.URL.Path))
+		case req.Method == http.MethodDelete:
+			t.Fatalf("expected no delete requests without PruneOrphanedBootstrapRoleBindings, got DELETE %s", req.URL.Path)
+			return
+		default:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+	c := &MasterConfig{
+		PrivilegedLoopbackOpenShiftClient: osClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					VerifyBootstrapRoleBindingIntegrity: true,
+				},
+			},
+		},
+	}
+
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	c.verifyBootstrapRoleBindingIntegrity(result)
+
+	if result.Counts["orphanedRoleBindingsDetected"] == 0 {
+		t.Errorf("expected orphanedRoleBindingsDetected to be recorded")
+	}
+	if result.Counts["orphanedRoleBindingsPruned"] != 0 {
+		t.Errorf("expected orphanedRoleBindingsPruned to stay 0 without the prune flag")
+	}
+}
+
+func TestReconcileNamespacedBootstrapRoleBindingsCountsConversionFailures(t *testing.T) {
+	roleBindings := map[string][]rbac.RoleBinding{
+		"default": {
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "bad-binding"},
+				RoleRef:    rbac.RoleRef{Kind: "Role", Name: "admin"},
+				Subjects:   []rbac.Subject{{Kind: "BogusKind", Name: "whoever"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "good-binding"},
+				RoleRef:    rbac.RoleRef{Kind: "Role", Name: "admin"},
+				Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "some-user"}},
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	options := ComponentAuthorizationRulesOptions{
+		OpenShiftClient: osClient,
+		Out:             ioutil.Discard,
+		Log:             glogBootstrapLogger{},
+	}
+	result := &BootstrapStepResult{Counts: map[string]int{}}
+	recordEvent := func(eventtype, reason, messageFmt string, args ...interface{}) {}
+
+	reconcileNamespacedBootstrapRoleBindings(context.Background(), roleBindings, options, recordEvent, result)
+
+	if result.Counts["namespacedRoleBindingConversionFailures"] != 1 {
+		t.Errorf("expected exactly one conversion failure to be counted, got %d", result.Counts["namespacedRoleBindingConversionFailures"])
+	}
+	if result.Counts["namespacedRoleBindingsReconciled"] != 1 {
+		t.Errorf("expected the valid binding to still be reconciled despite the other's conversion failure, got %d", result.Counts["namespacedRoleBindingsReconciled"])
+	}
+}
+
+// TestResolveBootstrapPolicyFileReturnsConfiguredPathUnchanged verifies that resolveBootstrapPolicyFile
+// passes an explicitly configured BootstrapPolicyFile straight through, with a cleanup func that's safe to
+// call but does not remove the caller's own file.
+func TestResolveBootstrapPolicyFileReturnsConfiguredPathUnchanged(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "bootstrap-policy")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	path, cleanup, err := resolveBootstrapPolicyFile(ComponentAuthorizationRulesOptions{BootstrapPolicyFile: tmpFile.Name()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != tmpFile.Name() {
+		t.Errorf("expected the configured path %q to be returned unchanged, got %q", tmpFile.Name(), path)
+	}
+
+	cleanup()
+	if _, err := os.Stat(tmpFile.Name()); err != nil {
+		t.Errorf("expected the caller's own file to survive cleanup, got %v", err)
+	}
+}
+
+// TestResolveBootstrapPolicyFileRendersEmbeddedDefaultWhenUnset verifies that resolveBootstrapPolicyFile
+// falls back to rendering the embedded default bootstrap policy to a temp file when BootstrapPolicyFile is
+// empty, that the rendered file is a valid, non-empty bootstrap policy template, and that cleanup removes it.
+func TestResolveBootstrapPolicyFileRendersEmbeddedDefaultWhenUnset(t *testing.T) {
+	path, cleanup, err := resolveBootstrapPolicyFile(ComponentAuthorizationRulesOptions{OpenShiftSharedResourcesNamespace: "openshift"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path) == 0 {
+		t.Fatalf("expected a rendered temp file path")
+	}
+
+	if err := validateBootstrapPolicyFile(path); err != nil {
+		t.Errorf("expected the rendered embedded default to be a valid bootstrap policy file, got %v", err)
+	}
+
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected cleanup to remove the rendered temp file, got %v", err)
+	}
+}
+
+// TestSkipDisabledBootstrapStepRecordsSkippedAndReturnsTrue verifies that a step named in
+// BootstrapOptions.DisabledSteps is reported as skipped and has "skipped" recorded on its BootstrapStepResult.
+func TestSkipDisabledBootstrapStepRecordsSkippedAndReturnsTrue(t *testing.T) {
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					DisabledSteps: map[string]bool{configapi.BootstrapStepSCC: true},
+				},
+			},
+		},
+	}
+	result := newBootstrapResult()
+
+	if !c.skipDisabledBootstrapStep(configapi.BootstrapStepSCC, result) {
+		t.Fatalf("expected the scc step to be reported as disabled")
+	}
+	if result.Steps[configapi.BootstrapStepSCC].Counts["skipped"] != 1 {
+		t.Errorf("expected the scc step to record a skipped count, got %v", result.Steps[configapi.BootstrapStepSCC].Counts)
+	}
+}
+
+// TestSkipDisabledBootstrapStepReturnsFalseWhenEnabled verifies that a step not named in
+// BootstrapOptions.DisabledSteps runs normally: it's reported as not disabled and nothing is recorded on its
+// BootstrapStepResult.
+func TestSkipDisabledBootstrapStepReturnsFalseWhenEnabled(t *testing.T) {
+	c := &MasterConfig{}
+	result := newBootstrapResult()
+
+	if c.skipDisabledBootstrapStep(configapi.BootstrapStepSCC, result) {
+		t.Fatalf("expected the scc step to be reported as enabled")
+	}
+	if _, ok := result.Steps[configapi.BootstrapStepSCC]; ok {
+		t.Errorf("expected no step result to be recorded for an enabled step")
+	}
+}
+
+// TestBootstrapStepOutcomeClassifiesStepResult verifies that bootstrapStepOutcome reports success only when
+// neither stepErr nor stepResult.Errors carry a failure, and failure - with the responsible error - otherwise.
+func TestBootstrapStepOutcomeClassifiesStepResult(t *testing.T) {
+	stepErr := errors.New("step failed")
+	recordedErr := errors.New("recorded during step")
+
+	if outcome, err := bootstrapStepOutcome(nil, &BootstrapStepResult{}); outcome != BootstrapStepOutcomeSuccess || err != nil {
+		t.Errorf("expected success with no error, got outcome=%v err=%v", outcome, err)
+	}
+	if outcome, err := bootstrapStepOutcome(stepErr, &BootstrapStepResult{}); outcome != BootstrapStepOutcomeFailure || err != stepErr {
+		t.Errorf("expected failure with stepErr, got outcome=%v err=%v", outcome, err)
+	}
+	if outcome, err := bootstrapStepOutcome(nil, &BootstrapStepResult{Errors: []error{recordedErr}}); outcome != BootstrapStepOutcomeFailure || err == nil {
+		t.Errorf("expected failure with the recorded error, got outcome=%v err=%v", outcome, err)
+	}
+}
+
+// TestEnsureBootstrapPolicyReportsSkippedStepsToCallback verifies that BootstrapStepCallback fires exactly
+// once per named bootstrap step, reporting BootstrapStepOutcomeSkipped for each, when every step is disabled
+// via BootstrapOptions.DisabledSteps - the simplest way to exercise every step's callback wiring without
+// standing up a full fake apiserver for each one.
+func TestEnsureBootstrapPolicyReportsSkippedStepsToCallback(t *testing.T) {
+	allSteps := []string{
+		configapi.BootstrapStepComponentAuthz,
+		configapi.BootstrapStepInfraNamespace,
+		configapi.BootstrapStepSharedResourcesNamespace,
+		configapi.BootstrapStepDefaultNamespaceSARoles,
+		configapi.BootstrapStepSCC,
+	}
+	disabled := map[string]bool{}
+	for _, step := range allSteps {
+		disabled[step] = true
+	}
+
+	var reported []string
+	outcomes := map[string]BootstrapStepOutcome{}
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fake.NewSimpleClientset(),
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace:  "openshift-infra",
+				OpenShiftSharedResourcesNamespace: "openshift",
+				Bootstrap:                         configapi.BootstrapOptions{DisabledSteps: disabled},
+			},
+		},
+		BootstrapStepCallback: func(step string, outcome BootstrapStepOutcome, err error) {
+			reported = append(reported, step)
+			outcomes[step] = outcome
+		},
+	}
+
+	if _, err := c.EnsureBootstrapPolicy(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reported) != len(allSteps) {
+		t.Fatalf("expected the callback to fire exactly once per step, got %v", reported)
+	}
+	for _, step := range allSteps {
+		if outcomes[step] != BootstrapStepOutcomeSkipped {
+			t.Errorf("expected step %q to report %v, got %v", step, BootstrapStepOutcomeSkipped, outcomes[step])
+		}
+	}
+}
+
+// mutationCountingObjectStore is a minimal stateful fake REST backend for exactly the request shape
+// osclient's ClusterRoles()/ClusterRoleBindings() issue: Get by name, Create, and Update, each keyed by the
+// full request path. It exists so a test can run a reconcile against it, then run the exact same reconcile
+// again and assert no further mutating (non-GET) requests were needed - proving the reconcile has actually
+// converged rather than merely not erroring.
+type mutationCountingObjectStore struct {
+	mu            sync.Mutex
+	objects       map[string][]byte
+	mutationCount int32
+}
+
+func (s *mutationCountingObjectStore) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Method {
+	case http.MethodGet:
+		body, ok := s.objects[req.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	case http.MethodPost:
+		body, _ := ioutil.ReadAll(req.Body)
+		name := objectNameFromJSON(body)
+		s.objects[req.URL.Path+"/"+name] = body
+		atomic.AddInt32(&s.mutationCount, 1)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	case http.MethodPut:
+		body, _ := ioutil.ReadAll(req.Body)
+		s.objects[req.URL.Path] = body
+		atomic.AddInt32(&s.mutationCount, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// mutations returns the number of Create/Update calls the store has recorded so far.
+func (s *mutationCountingObjectStore) mutations() int32 {
+	return atomic.LoadInt32(&s.mutationCount)
+}
+
+// objectNameFromJSON extracts metadata.name from a JSON-encoded API object, for keying a newly created
+// object's path the same way a Get for it will later be addressed.
+func objectNameFromJSON(body []byte) string {
+	var obj struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(body, &obj)
+	return obj.Metadata.Name
+}
+
+// TestReconcileComponentAuthorizationRulesSecondRunHasNoMutationsOnceConverged is the idempotency proof
+// requested for EnsureBootstrapPolicy's core reconcile step: running ReconcileComponentAuthorizationRules
+// against a fake apiserver that starts out with no cluster roles or role bindings creates them on the first
+// run (a real mutation), then running it again against that same, now-converged store issues zero further
+// Create/Update calls - proving the reconcile doesn't keep re-writing objects that already match the bootstrap
+// definition.
+func TestReconcileComponentAuthorizationRulesSecondRunHasNoMutationsOnceConverged(t *testing.T) {
+	store := &mutationCountingObjectStore{objects: map[string][]byte{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	osClient, err := osclient.New(&restclient.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	registry := staleClusterPolicyRegistry{policy: &authorizationapi.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: authorizationapi.PolicyName},
+	}}
+
+	runOnce := func() *BootstrapStepResult {
+		result := &BootstrapStepResult{Counts: map[string]int{}}
+		ReconcileComponentAuthorizationRules(context.Background(), ComponentAuthorizationRulesOptions{
+			ClusterPolicyRegistry:    registry,
+			OpenShiftClient:          osClient,
+			ReconcileNamespacedRoles: func(clusterPolicyMissing bool) bool { return false },
+			RolesToReconcile:         []string{bootstrappolicy.DiscoveryRoleName},
+			Out:                      ioutil.Discard,
+		}, result)
+		return result
+	}
+
+	firstResult := runOnce()
+	if len(firstResult.Errors) != 0 {
+		t.Fatalf("unexpected errors on first run: %v", firstResult.Errors)
+	}
+	firstRunMutations := store.mutations()
+	if firstRunMutations == 0 {
+		t.Fatalf("expected the first run against an empty store to create the discovery role and rolebinding")
+	}
+
+	secondResult := runOnce()
+	if len(secondResult.Errors) != 0 {
+		t.Fatalf("unexpected errors on second run: %v", secondResult.Errors)
+	}
+	if secondRunMutations := store.mutations() - firstRunMutations; secondRunMutations != 0 {
+		t.Errorf("expected the second run to be a no-op now that the store matches the bootstrap definition, got %d mutating calls", secondRunMutations)
+	}
+}
+
+// TestDumpBootstrapPolicyPlanLogsAtV4 verifies that dumpBootstrapPolicyPlan emits the computed plan - naming
+// the infra namespace, a known bootstrap cluster role, and a known bootstrap security context constraint -
+// when V(4) is enabled, and stays silent otherwise, since the whole point of the V(4) guard is to avoid the
+// wasted work of assembling the plan when nobody's going to read it.
+func TestDumpBootstrapPolicyPlanLogsAtV4(t *testing.T) {
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+			},
+		},
+	}
+
+	captureDumpOutput := func(t *testing.T, verbosity string) string {
+		vFlag := flag.Lookup("v")
+		previousV := vFlag.Value.String()
+		if err := vFlag.Value.Set(verbosity); err != nil {
+			t.Fatalf("could not set glog verbosity: %v", err)
+		}
+		defer vFlag.Value.Set(previousV)
+
+		previousLogtostderr := flag.Lookup("logtostderr").Value.String()
+		flag.Set("logtostderr", "true")
+		defer flag.Set("logtostderr", previousLogtostderr)
+
+		previousStderr := os.Stderr
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("could not create pipe: %v", err)
+		}
+		os.Stderr = w
+
+		c.dumpBootstrapPolicyPlan()
+
+		w.Close()
+		os.Stderr = previousStderr
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	t.Run("V4 enabled", func(t *testing.T) {
+		output := captureDumpOutput(t, "4")
+
+		if !strings.Contains(output, "Computed bootstrap plan") {
+			t.Fatalf("expected the dump to log the computed plan, got: %s", output)
+		}
+		if !strings.Contains(output, ns) {
+			t.Errorf("expected the dump to name the infra namespace %q, got: %s", ns, output)
+		}
+		if !strings.Contains(output, bootstrappolicy.DiscoveryRoleName) {
+			t.Errorf("expected the dump to name a known bootstrap cluster role, got: %s", output)
+		}
+		groups, users := bootstrappolicy.GetBoostrapSCCAccess(ns)
+		sccName := bootstrappolicy.GetBootstrapSecurityContextConstraints(groups, users)[0].Name
+		if !strings.Contains(output, sccName) {
+			t.Errorf("expected the dump to name a known bootstrap security context constraint %q, got: %s", sccName, output)
+		}
+	})
+
+	t.Run("V4 disabled", func(t *testing.T) {
+		output := captureDumpOutput(t, "0")
+
+		if strings.Contains(output, "Computed bootstrap plan") {
+			t.Errorf("expected no plan dump below V(4), got: %s", output)
+		}
+	})
+}
+
+// fakeDiscoveryClient is a minimal discovery.DiscoveryInterface for exercising detectSCCAPIGroup with a
+// canned ServerGroups response, without standing up a fake apiserver.
+type fakeDiscoveryClient struct {
+	discovery.DiscoveryInterface
+	groups *metav1.APIGroupList
+	err    error
+}
+
+func (f *fakeDiscoveryClient) ServerGroups() (*metav1.APIGroupList, error) {
+	return f.groups, f.err
+}
+
+func apiGroupList(names ...string) *metav1.APIGroupList {
+	list := &metav1.APIGroupList{}
+	for _, name := range names {
+		list.Groups = append(list.Groups, metav1.APIGroup{Name: name})
+	}
+	return list
+}
+
+func TestDetectSCCAPIGroupPrefersNativeGroupWhenAdvertised(t *testing.T) {
+	client := &fakeDiscoveryClient{groups: apiGroupList("apps", securityapiv1.GroupName, "build.openshift.io")}
+
+	group, err := detectSCCAPIGroup(client, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group != securityapiv1.GroupName {
+		t.Errorf("expected %q, got %q", securityapiv1.GroupName, group)
+	}
+}
+
+func TestDetectSCCAPIGroupFallsBackToLegacyWhenNativeGroupAbsent(t *testing.T) {
+	client := &fakeDiscoveryClient{groups: apiGroupList("apps", "build.openshift.io")}
+
+	group, err := detectSCCAPIGroup(client, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group != securityapiv1.LegacyGroupName {
+		t.Errorf("expected %q, got %q", securityapiv1.LegacyGroupName, group)
+	}
+}
+
+func TestDetectSCCAPIGroupHonorsLegacyOverrideWithoutConsultingDiscovery(t *testing.T) {
+	client := &fakeDiscoveryClient{err: fmt.Errorf("discovery should not be called")}
+
+	group, err := detectSCCAPIGroup(client, sccAPIGroupOverrideLegacy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group != securityapiv1.LegacyGroupName {
+		t.Errorf("expected %q, got %q", securityapiv1.LegacyGroupName, group)
+	}
+}
+
+func TestDetectSCCAPIGroupHonorsNativeOverrideWithoutConsultingDiscovery(t *testing.T) {
+	client := &fakeDiscoveryClient{err: fmt.Errorf("discovery should not be called")}
+
+	group, err := detectSCCAPIGroup(client, sccAPIGroupOverrideNative)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group != securityapiv1.GroupName {
+		t.Errorf("expected %q, got %q", securityapiv1.GroupName, group)
+	}
+}
+
+func TestDetectSCCAPIGroupRejectsUnrecognizedOverride(t *testing.T) {
+	client := &fakeDiscoveryClient{groups: apiGroupList()}
+
+	if _, err := detectSCCAPIGroup(client, "bogus"); err == nil {
+		t.Fatal("expected an error for an unrecognized override, got none")
+	}
+}
+
+func TestDetectSCCAPIGroupPropagatesDiscoveryError(t *testing.T) {
+	client := &fakeDiscoveryClient{err: fmt.Errorf("discovery unavailable")}
+
+	if _, err := detectSCCAPIGroup(client, ""); err == nil {
+		t.Fatal("expected discovery's error to propagate, got none")
+	}
+}
+
+// TestWaitForAPIServerReadyProceedsOnceReadinessProbeSucceeds verifies that a client which errors on the
+// first several readiness probes and then succeeds is retried until it succeeds, rather than failing on the
+// first attempt.
+func TestWaitForAPIServerReadyProceedsOnceReadinessProbeSucceeds(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	var attempts int32
+	fakeKubeClient.PrependReactor("list", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			return true, nil, fmt.Errorf("apiserver not ready yet")
+		}
+		return true, &kapi.NamespaceList{}, nil
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{ReadinessTimeoutSeconds: 5},
+			},
+		},
+	}
+
+	if err := c.waitForAPIServerReady(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("expected exactly 3 readiness probes (2 failures then a success), got %d", got)
+	}
+}
+
+// TestWaitForAPIServerReadyReturnsDescriptiveErrorWhenNeverReady verifies that a client which never succeeds
+// produces a clear timeout error rather than the underlying probe error alone, once the configured bound
+// elapses.
+func TestWaitForAPIServerReadyReturnsDescriptiveErrorWhenNeverReady(t *testing.T) {
+	fakeKubeClient := fake.NewSimpleClientset()
+	fakeKubeClient.PrependReactor("list", "namespaces", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: fakeKubeClient,
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{ReadinessTimeoutSeconds: 1},
+			},
+		},
+	}
+
+	err := c.waitForAPIServerReady(context.Background())
+	if err == nil {
+		t.Fatal("expected an error when the apiserver never becomes ready")
+	}
+	if !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the underlying probe error to be included, got: %v", err)
+	}
+}
+
+// TestDumpBootstrapConfigRoundTripsThroughYAML verifies that DumpBootstrapConfig produces a YAML document
+// that parses back into the expected shape and names the infra namespace, a known bootstrap cluster role, a
+// known bootstrap security context constraint, and the configured bootstrap policy file.
+func TestDumpBootstrapConfigRoundTripsThroughYAML(t *testing.T) {
+	ns := "openshift-infra"
+	c := &MasterConfig{
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				OpenShiftInfrastructureNamespace: ns,
+				BootstrapPolicyFile:              "/etc/origin/master/policy.json",
+				Bootstrap: configapi.BootstrapOptions{
+					NamespaceInitConcurrency: 4,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := c.DumpBootstrapConfig(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var dump bootstrapConfigDump
+	if err := yaml.Unmarshal(buf.Bytes(), &dump); err != nil {
+		t.Fatalf("dump did not round-trip through YAML: %v\ncontent:\n%s", err, buf.String())
+	}
+
+	if dump.Options.NamespaceInitConcurrency != 4 {
+		t.Errorf("expected the effective BootstrapOptions to be included, got %+v", dump.Options)
+	}
+	if dump.BootstrapPolicyFile != "/etc/origin/master/policy.json" {
+		t.Errorf("expected bootstrapPolicyFile to be %q, got %q", "/etc/origin/master/policy.json", dump.BootstrapPolicyFile)
+	}
+	found := sets.NewString(dump.Namespaces...)
+	if !found.Has(ns) {
+		t.Errorf("expected namespaces to include the infra namespace %q, got %v", ns, dump.Namespaces)
+	}
+	if len(dump.ClusterRoles) == 0 {
+		t.Errorf("expected at least one bootstrap cluster role name")
+	}
+	if len(dump.SecurityContextConstraints) == 0 {
+		t.Errorf("expected at least one bootstrap security context constraint name")
+	}
+
+	for _, key := range []string{"options:", "namespaces:", "clusterRoles:", "clusterRoleBindings:", "securityContextConstraints:", "bootstrapPolicyFile:"} {
+		if !strings.Contains(buf.String(), key) {
+			t.Errorf("expected dump to contain YAML key %q, got:\n%s", key, buf.String())
+		}
+	}
+}
+
+// TestBootstrapResultTotalsSumsCountsAcrossSteps verifies that categories missing from a given step's Counts
+// contribute zero rather than panicking or being mistaken for another category, and that "reconciled" and
+// "updated" are both folded into the totals' updated count.
+func TestBootstrapResultTotalsSumsCountsAcrossSteps(t *testing.T) {
+	result := newBootstrapResult()
+	result.step(configapi.BootstrapStepComponentAuthz).Counts["created"] = 3
+	result.step(configapi.BootstrapStepComponentAuthz).Counts["reconciled"] = 2
+	result.step(configapi.BootstrapStepSCC).Counts["created"] = 1
+	result.step(configapi.BootstrapStepSCC).Counts["skipped"] = 4
+	result.step(configapi.BootstrapStepInfraNamespace).Counts["updated"] = 5
+
+	steps, created, updated, skipped := bootstrapResultTotals(result)
+	if steps != 3 {
+		t.Errorf("expected steps=3, got %d", steps)
+	}
+	if created != 4 {
+		t.Errorf("expected created=4, got %d", created)
+	}
+	if updated != 7 {
+		t.Errorf("expected updated=7 (reconciled + updated), got %d", updated)
+	}
+	if skipped != 4 {
+		t.Errorf("expected skipped=4, got %d", skipped)
+	}
+}
+
+// TestLogBootstrapOutcomeLogsSummaryLineOnSuccess verifies that the completed-run summary line reports the
+// totals bootstrapResultTotals computes from result.
+func TestLogBootstrapOutcomeLogsSummaryLineOnSuccess(t *testing.T) {
+	log := &fakeBootstrapLogger{}
+	c := &MasterConfig{BootstrapLog: log}
+	result := newBootstrapResult()
+	result.step(configapi.BootstrapStepSCC).Counts["created"] = 2
+
+	c.logBootstrapOutcome(result, "", nil)
+
+	if len(log.infos) != 1 {
+		t.Fatalf("expected exactly one Info call, got %v", log.infos)
+	}
+	want := "Bootstrap policy initialization completed: 1 steps, 2 created, 0 updated, 0 skipped"
+	if log.infos[0] != want {
+		t.Errorf("expected summary line %q, got %q", want, log.infos[0])
+	}
+	if len(log.errors) != 0 {
+		t.Errorf("expected no Error calls on success, got %v", log.errors)
+	}
+}
+
+// TestLogBootstrapOutcomeLogsSummaryLineOnFailure verifies that a failed step's name and error both appear in
+// the single summary line, and that it's logged via Info like the success line rather than Error, so it's
+// found by the same grep regardless of outcome.
+func TestLogBootstrapOutcomeLogsSummaryLineOnFailure(t *testing.T) {
+	log := &fakeBootstrapLogger{}
+	c := &MasterConfig{BootstrapLog: log}
+	stepErr := errors.New("could not reconcile cluster roles")
+
+	c.logBootstrapOutcome(newBootstrapResult(), configapi.BootstrapStepComponentAuthz, stepErr)
+
+	if len(log.infos) != 1 {
+		t.Fatalf("expected exactly one Info call, got %v", log.infos)
+	}
+	want := fmt.Sprintf("Bootstrap policy initialization failed at step %s: %v", configapi.BootstrapStepComponentAuthz, stepErr)
+	if log.infos[0] != want {
+		t.Errorf("expected summary line %q, got %q", want, log.infos[0])
+	}
+	if len(log.errors) != 0 {
+		t.Errorf("expected no Error calls, got %v", log.errors)
+	}
+}
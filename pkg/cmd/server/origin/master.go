@@ -1,6 +1,7 @@
 package origin
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/url"
@@ -312,16 +313,9 @@ func (c *MasterConfig) buildHandlerChain(assetConfig *AssetConfig) (func(http.Ha
 // InitializeObjects ensures objects in Kubernetes and etcd are properly populated.
 // Requires a Kube client to be established and that etcd be started.
 func (c *MasterConfig) InitializeObjects() {
-	// Create required policy rules if needed
-	c.ensureComponentAuthorizationRules()
-	// Ensure the default SCCs are created
-	c.ensureDefaultSecurityContextConstraints()
-	// Bind default roles for service accounts in the default namespace if needed
-	c.ensureDefaultNamespaceServiceAccountRoles()
-	// Create the infra namespace
-	c.ensureOpenShiftInfraNamespace()
-	// Create the shared resource namespace
-	c.ensureOpenShiftSharedResourcesNamespace()
+	if _, err := c.EnsureBootstrapPolicy(context.TODO()); err != nil {
+		glog.Errorf("Error initializing bootstrap policy: %v", err)
+	}
 }
 
 // getRequestContextMapper returns a mapper from requests to contexts, initializing it if needed
@@ -3,12 +3,16 @@ package origin
 import (
 	"crypto/x509"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"path"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
@@ -18,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
@@ -36,9 +41,11 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
 	authorizerunion "k8s.io/apiserver/pkg/authorization/union"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/discovery"
 	kubeclientgoinformers "k8s.io/client-go/informers"
 	kubeclientgoclient "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/cert"
 	kapi "k8s.io/kubernetes/pkg/api"
 	kclientsetexternal "k8s.io/kubernetes/pkg/client/clientset_generated/clientset"
@@ -64,6 +71,8 @@ import (
 	"github.com/openshift/origin/pkg/authorization/authorizer/scope"
 	authorizationinformer "github.com/openshift/origin/pkg/authorization/generated/informers/internalversion"
 	authorizationinternalinformer "github.com/openshift/origin/pkg/authorization/generated/informers/internalversion/authorization/internalversion"
+	clusterpolicyregistry "github.com/openshift/origin/pkg/authorization/registry/clusterpolicy"
+	clusterpolicystorage "github.com/openshift/origin/pkg/authorization/registry/clusterpolicy/etcd"
 	"github.com/openshift/origin/pkg/authorization/rulevalidation"
 	buildinformer "github.com/openshift/origin/pkg/build/generated/informers/internalversion"
 	osclient "github.com/openshift/origin/pkg/client"
@@ -95,7 +104,9 @@ import (
 	"github.com/openshift/origin/pkg/quota/controller/clusterquotamapping"
 	quotainformer "github.com/openshift/origin/pkg/quota/generated/informers/internalversion"
 
+	securityapiv1 "github.com/openshift/origin/pkg/security/apis/security/v1"
 	securityinformer "github.com/openshift/origin/pkg/security/generated/informers/internalversion"
+	"github.com/openshift/origin/pkg/security/legacyclient"
 	"github.com/openshift/origin/pkg/service"
 	serviceadmit "github.com/openshift/origin/pkg/service/admission"
 	templateinformer "github.com/openshift/origin/pkg/template/generated/informers/internalversion"
@@ -189,8 +200,323 @@ type MasterConfig struct {
 	QuotaInformers         quotainformer.SharedInformerFactory
 	SecurityInformers      securityinformer.SharedInformerFactory
 	TemplateInformers      templateinformer.SharedInformerFactory
+
+	// DefaultNamespaceWaitTimeout is how long ensureDefaultNamespaceServiceAccountRoles waits for the default
+	// namespace to appear before giving up. Defaults to DefaultNamespaceWaitTimeoutDefault when zero.
+	DefaultNamespaceWaitTimeout time.Duration
+
+	// WatchDefaultNamespace controls whether ensureDefaultNamespaceServiceAccountRoles waits for the default
+	// namespace by establishing a Watch instead of polling once a second. Falls back to polling if the watch
+	// can't be established.
+	WatchDefaultNamespace bool
+
+	// BootstrapEventRecorder, when set, is used by the ensure* bootstrap methods to emit Events recording
+	// what bootstrap policy initialization did. It is optional; unit tests may leave it nil.
+	BootstrapEventRecorder record.EventRecorder
+
+	// DryRunBootstrap, when true, causes the ensure* bootstrap methods to compute and log the Creates/Updates
+	// they would make without sending them to the apiserver, so operators can preview a bootstrap policy
+	// change before it takes effect.
+	DryRunBootstrap bool
+
+	// BootstrapOutput is where planned changes are described when DryRunBootstrap is set. Defaults to
+	// ioutil.Discard when nil, matching the non-dry-run behavior of discarding reconcile diff output.
+	BootstrapOutput io.Writer
+
+	// ForceReinitializeServiceAccountRoles, when true, causes ensureNamespaceServiceAccountRoleBindings to
+	// skip its openshift.io/sa.initialized-roles short-circuit and re-apply the bootstrap service account
+	// role bindings unconditionally. Useful for rebuilding bindings that were manually deleted or corrupted
+	// after a namespace was already marked initialized. AddRole is idempotent, so re-running is safe.
+	ForceReinitializeServiceAccountRoles bool
+
+	// ForceFullBootstrap, when true, causes EnsureBootstrapPolicy to skip its version-precheck fast path and
+	// always run every ensure* step, even if the infra namespace's bootstrap-master-version annotation
+	// already matches this binary. Useful when an admin suspects drift the fast path can't see (for example
+	// after manually editing bootstrapped objects) and wants a full reconcile regardless of the recorded
+	// version.
+	ForceFullBootstrap bool
+
+	// BootstrapLog is the structured logger the ensure* bootstrap methods use to report what they did.
+	// Defaults to a glog-backed adapter when nil, so log lines still land in the usual place but carry
+	// filterable key/value fields (namespace=, role=, step=, ...) instead of burying identifiers in a format
+	// string. Tests can set this to capture and assert on logged fields.
+	BootstrapLog BootstrapLogger
+
+	// BootstrapTracer, when set, receives a span for each EnsureBootstrapPolicy step (and, where applicable,
+	// the namespace it's operating on), tagged with the step name and whether it failed. Left nil, tracing is
+	// a no-op, so non-traced deployments pay nothing beyond the interface check.
+	BootstrapTracer BootstrapTracer
+
+	// SCCClient is used by ensureDefaultSecurityContextConstraints to create, reconcile, and prune bootstrap
+	// SecurityContextConstraints. Defaults to a legacyclient wrapping the privileged loopback Kube client's
+	// REST client when nil, so unit tests can inject a fake without standing up an apiserver.
+	SCCClient legacyclient.SecurityContextConstraintInterface
+
+	// BootstrapAuditor, when set, receives a BootstrapAuditRecord for every privileged mutation the ensure*
+	// bootstrap methods make. Left nil, mutations are simply not recorded, so non-audited deployments pay
+	// nothing beyond the no-op call.
+	BootstrapAuditor BootstrapAuditor
+
+	// BootstrapStepCallback, when set, is invoked once after each EnsureBootstrapPolicy step - including a
+	// step skipped via BootstrapOptions.DisabledSteps - with the step's name and outcome. Unlike bootstrap
+	// metrics/tracing/events, this is a synchronous in-process hook rather than an out-of-band sink, so a
+	// cluster operator can translate outcomes into ClusterOperator status conditions in real time as bootstrap
+	// runs. Left nil, it costs nothing beyond the nil check.
+	BootstrapStepCallback BootstrapStepCallback
+
+	// NamespaceCreationObjects, when set, is called with the name of a namespace ensureOpenShiftInfraNamespace
+	// or ensureOpenShiftSharedResourcesNamespace is about to ensure, returning any extra objects (a
+	// default-deny NetworkPolicy, a ResourceQuota, ...) that should exist in it. The returned objects must
+	// carry a populated TypeMeta, since they're applied through a dynamic client rather than the master's own
+	// scheme. Left nil, no extra objects are created, so deployments that don't need this pay nothing beyond
+	// the nil check. Objects are applied idempotently - an object that already exists is left alone - so the
+	// hook can be invoked on every bootstrap run without caring whether the namespace was just created.
+	NamespaceCreationObjects func(namespace string) []runtime.Object
+
+	// NamespaceObjectCreator applies the objects NamespaceCreationObjects returns. Defaults to a dynamic
+	// client-backed implementation when nil; tests can set this to a fake to assert on what would have been
+	// created without standing up an apiserver capable of serving arbitrary types.
+	NamespaceObjectCreator NamespaceObjectCreator
+
+	// NamespaceMutator, when set, is applied to a bootstrap namespace's object just before it's created, and
+	// to the patched fields of an already-existing namespace, letting an operator set a finalizer, owner
+	// reference, or custom annotation that isn't covered by BootstrapNamespaceLabels or InfraNamespaceLabels.
+	// This is a generic escape hatch specifically so one-off customizations don't need a dedicated config
+	// field each. Left nil, no mutation is applied, so deployments that don't need this pay nothing beyond the
+	// nil check.
+	NamespaceMutator func(*kapi.Namespace)
+
+	// BootstrapReconcileLoopClock is the clock StartBootstrapReconcileLoop schedules its ticks with.
+	// Defaults to the real wall clock when nil. Tests set this to a clock.FakeClock so the reconcile loop can
+	// be driven deterministically instead of waiting on real time.
+	BootstrapReconcileLoopClock clock.Clock
+
+	// postBootstrapHooks holds the hooks registered via RegisterPostBootstrapHook, run in registration order
+	// by EnsureBootstrapPolicy once the required bootstrap steps have succeeded. Access is synchronized since
+	// hooks may be registered by init-time code running concurrently with master startup.
+	postBootstrapHooks   []postBootstrapHook
+	postBootstrapHooksMu sync.Mutex
+
+	// bootstrapComplete is 1 once EnsureBootstrapPolicy has run every required step successfully, and 0
+	// otherwise. Set exclusively via setBootstrapComplete and read via BootstrapComplete; accessed with
+	// sync/atomic rather than a mutex since it's a single flag read far more often (every readiness probe)
+	// than it's written (once, ever, per master process).
+	bootstrapComplete int32
+
+	// bootstrapRunning is 1 while a call to EnsureBootstrapPolicy is in progress on this MasterConfig, and 0
+	// otherwise. EnsureBootstrapPolicy claims it with an atomic compare-and-swap and clears it on return,
+	// rejecting a second concurrent call with ErrBootstrapAlreadyRunning rather than letting two runs
+	// double-apply the ensure* steps against the same cluster state.
+	bootstrapRunning int32
+
+	// loopbackUnauthorizedCount tracks how many Forbidden/Unauthorized reconcile failures
+	// classifyLoopbackAuthFailure has seen against the privileged loopback client over the life of this
+	// MasterConfig. Accessed with sync/atomic; once it reaches defaultLoopbackUnauthorizedThreshold,
+	// classifyLoopbackAuthFailure starts wrapping errors as ErrLoopbackUnauthorized.
+	loopbackUnauthorizedCount int32
+
+	// clusterPolicyRegistry is the lazily-built, cached ClusterPolicy registry used by
+	// ensureComponentAuthorizationRules. It's built once via clusterPolicyRegistryLazy rather than on every
+	// call, so repeated bootstrap reconciliation doesn't keep constructing fresh etcd-backed storage. Tests
+	// may set this directly to inject a fake registry, bypassing clusterPolicyRegistryOnce.
+	clusterPolicyRegistry     clusterpolicyregistry.Registry
+	clusterPolicyRegistryOnce sync.Once
+	clusterPolicyRegistryErr  error
+}
+
+// clusterPolicyRegistryLazy returns the cached ClusterPolicy registry, building it from c.RESTOptionsGetter
+// on first use and reusing it on every subsequent call.
+func (c *MasterConfig) clusterPolicyRegistryLazy() (clusterpolicyregistry.Registry, error) {
+	c.clusterPolicyRegistryOnce.Do(func() {
+		if c.clusterPolicyRegistry != nil {
+			return
+		}
+		clusterPolicyStorage, err := clusterpolicystorage.NewREST(c.RESTOptionsGetter)
+		if err != nil {
+			c.clusterPolicyRegistryErr = err
+			return
+		}
+		c.clusterPolicyRegistry = clusterpolicyregistry.NewRegistry(clusterPolicyStorage)
+	})
+	return c.clusterPolicyRegistry, c.clusterPolicyRegistryErr
+}
+
+// bootstrapOutput returns the configured BootstrapOutput, falling back to ioutil.Discard so callers always
+// have a safe io.Writer to pass to reconcile helpers.
+func (c *MasterConfig) bootstrapOutput() io.Writer {
+	if c.BootstrapOutput != nil {
+		return c.BootstrapOutput
+	}
+	return ioutil.Discard
+}
+
+// bootstrapLog returns the configured BootstrapLog, falling back to a glog-backed adapter so callers always
+// have a logger to write to.
+func (c *MasterConfig) bootstrapLog() BootstrapLogger {
+	if c.BootstrapLog != nil {
+		return c.BootstrapLog
+	}
+	return glogBootstrapLogger{}
+}
+
+// setBootstrapComplete records that EnsureBootstrapPolicy has finished successfully. It must only be called
+// after every required (non-best-effort) bootstrap step has succeeded, since BootstrapComplete is meant to
+// gate readiness probes on bootstrap actually having completed.
+func (c *MasterConfig) setBootstrapComplete() {
+	atomic.StoreInt32(&c.bootstrapComplete, 1)
+}
+
+// BootstrapComplete reports whether EnsureBootstrapPolicy has finished all of its required steps
+// successfully. It's safe to call concurrently with EnsureBootstrapPolicy and with itself.
+func (c *MasterConfig) BootstrapComplete() bool {
+	return atomic.LoadInt32(&c.bootstrapComplete) == 1
+}
+
+// BootstrapCompleteHandler returns an http.HandlerFunc suitable for wiring up as a readiness endpoint: it
+// responds 200 once BootstrapComplete is true, and 503 until then, so probes can gate traffic on bootstrap
+// policy initialization having actually finished rather than just on apiserver liveness.
+func (c *MasterConfig) BootstrapCompleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !c.BootstrapComplete() {
+			http.Error(w, "bootstrap policy initialization not yet complete", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// overwriteBootstrapPolicyIfMissing reports whether ensureComponentAuthorizationRules should seed cluster
+// policy from PolicyConfig.BootstrapPolicyFile when no cluster policy exists yet. Defaults to true when
+// PolicyConfig.Bootstrap.OverwriteBootstrapPolicyIfMissing is unset, preserving the historical behavior of always
+// seeding a fresh cluster's policy from file.
+func (c *MasterConfig) overwriteBootstrapPolicyIfMissing() bool {
+	flag := c.Options.PolicyConfig.Bootstrap.OverwriteBootstrapPolicyIfMissing
+	return flag == nil || *flag
+}
+
+// reconcileNamespacedBootstrapRoles reports whether ensureComponentAuthorizationRules should reconcile
+// namespaced bootstrap roles and role bindings, given whether cluster policy is currently missing. When
+// PolicyConfig.Bootstrap.ReconcileNamespacedBootstrapRoles is unset, this mirrors the historical, combined behavior of
+// only reconciling namespaced roles alongside a missing-cluster-policy bootstrap. Setting the flag
+// explicitly makes the decision unconditional, so a disaster-recovery restart can reconcile namespaced
+// roles without also reseeding cluster policy from file (see overwriteBootstrapPolicyIfMissing).
+func (c *MasterConfig) reconcileNamespacedBootstrapRoles(clusterPolicyMissing bool) bool {
+	if flag := c.Options.PolicyConfig.Bootstrap.ReconcileNamespacedBootstrapRoles; flag != nil {
+		return *flag
+	}
+	return clusterPolicyMissing
 }
 
+const (
+	// sccAPIGroupOverrideLegacy forces detectSCCAPIGroup to report the legacy (unprefixed) SCC endpoint
+	// regardless of what discovery advertises.
+	sccAPIGroupOverrideLegacy = "legacy"
+	// sccAPIGroupOverrideNative forces detectSCCAPIGroup to report the security.openshift.io SCC endpoint
+	// regardless of what discovery advertises.
+	sccAPIGroupOverrideNative = "security.openshift.io"
+)
+
+// detectSCCAPIGroup picks the SCC API group bootstrap should target: securityapiv1.LegacyGroupName (the
+// original, unprefixed endpoint) or securityapiv1.GroupName (security.openshift.io), the newer group
+// clusters are migrating SCCs to. override, when non-empty, short-circuits discovery and must be one of
+// sccAPIGroupOverrideLegacy or sccAPIGroupOverrideNative. Left empty, the group is auto-detected by
+// listing server groups and preferring security.openshift.io when the apiserver advertises it, falling
+// back to the legacy group otherwise.
+func detectSCCAPIGroup(discoveryClient discovery.DiscoveryInterface, override string) (string, error) {
+	switch override {
+	case sccAPIGroupOverrideNative:
+		return securityapiv1.GroupName, nil
+	case sccAPIGroupOverrideLegacy:
+		return securityapiv1.LegacyGroupName, nil
+	case "":
+		// fall through to discovery below
+	default:
+		return "", fmt.Errorf("unrecognized SCCAPIGroupOverride %q; expected %q, %q, or empty to auto-detect", override, sccAPIGroupOverrideLegacy, sccAPIGroupOverrideNative)
+	}
+
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return "", err
+	}
+	for _, group := range groups.Groups {
+		if group.Name == securityapiv1.GroupName {
+			return securityapiv1.GroupName, nil
+		}
+	}
+	return securityapiv1.LegacyGroupName, nil
+}
+
+// bootstrapKubeClient returns the apiserver client the bootstrap ensure* methods use to create and reconcile
+// cluster-scoped and namespaced resources. When PolicyConfig.Bootstrap.ClientConnectionOverrides is set, this
+// is a dedicated client scoped to those QPS/burst settings, built from a copy of PrivilegedLoopbackClientConfig
+// - so bootstrap's initial reconcile storm on a fresh cluster (creating every bootstrap namespace, role
+// binding, and SCC in one pass) can't consume the shared loopback client's QPS/burst budget and starve other
+// in-process controllers. Falls back to the shared loopback client, unscoped, when unset or if constructing
+// the scoped client fails.
+func (c *MasterConfig) bootstrapKubeClient() kclientsetinternal.Interface {
+	overrides := c.Options.PolicyConfig.Bootstrap.ClientConnectionOverrides
+	if overrides == nil {
+		return c.KubeClientsetInternal()
+	}
+	scopedClient, err := kclientsetinternal.NewForConfig(bootstrapClientConfig(c.PrivilegedLoopbackClientConfig, overrides))
+	if err != nil {
+		c.bootstrapLog().Error(err, "Unable to construct a scoped bootstrap apiserver client; falling back to the shared loopback client")
+		return c.KubeClientsetInternal()
+	}
+	return scopedClient
+}
+
+// bootstrapClientConfig returns a copy of base with its QPS/Burst replaced by overrides, leaving every other
+// setting - host, credentials, TLS - untouched. Split out from bootstrapKubeClient so the scoping logic can be
+// asserted directly without standing up a client against it.
+func bootstrapClientConfig(base restclient.Config, overrides *configapi.ClientConnectionOverrides) *restclient.Config {
+	scoped := base
+	scoped.QPS = overrides.QPS
+	scoped.Burst = int(overrides.Burst)
+	return &scoped
+}
+
+// sccClient returns the configured SCCClient, falling back to a legacyclient wrapping the privileged
+// loopback Kube client's REST client so callers always have a SecurityContextConstraintInterface to use.
+//
+// detectSCCAPIGroup is consulted so misconfiguration of SCCAPIGroupOverride and discovery failures
+// surface in the log even though the fallback below always targets the legacy endpoint: this snapshot's
+// generated security.openshift.io clientset (pkg/security/generated/internalclientset) doesn't have a
+// typed client checked in for it yet, so there's no second real SecurityContextConstraintInterface
+// implementation to route to. Once that client lands, this is the place to return it for the native group.
+func (c *MasterConfig) sccClient() legacyclient.SecurityContextConstraintInterface {
+	if c.SCCClient != nil {
+		return c.SCCClient
+	}
+	if group, err := detectSCCAPIGroup(c.KubeClientsetInternal().Discovery(), c.Options.PolicyConfig.Bootstrap.SCCAPIGroupOverride); err != nil {
+		c.bootstrapLog().Error(err, "Could not determine security context constraint API group; defaulting to the legacy endpoint")
+	} else if group != securityapiv1.LegacyGroupName {
+		c.bootstrapLog().Info("Detected security context constraint API group without a wired-up client; continuing to use the legacy endpoint", "group", group)
+	}
+	return legacyclient.NewFromClient(c.KubeClientsetInternal().Core().RESTClient())
+}
+
+// namespaceCreationObjects returns whatever extra objects NamespaceCreationObjects (if set) wants created in
+// ns, or nil when no hook is configured.
+func (c *MasterConfig) namespaceCreationObjects(ns string) []runtime.Object {
+	if c.NamespaceCreationObjects == nil {
+		return nil
+	}
+	return c.NamespaceCreationObjects(ns)
+}
+
+// DefaultNamespaceWaitTimeoutDefault is the default value of MasterConfig.DefaultNamespaceWaitTimeout.
+const DefaultNamespaceWaitTimeoutDefault = 30 * time.Second
+
+// DefaultNamespaceWaitInterval is the initial polling interval used while waiting for the default namespace to
+// appear. The interval doubles after each failed attempt, up to DefaultNamespaceWaitMaxInterval, so a slow
+// cluster doesn't have to make the namespace appear within a fixed number of one-second polls.
+const DefaultNamespaceWaitInterval = time.Second
+
+// DefaultNamespaceWaitMaxInterval caps the exponential backoff applied to DefaultNamespaceWaitInterval, so a
+// long DefaultNamespaceWaitTimeout doesn't leave the master polling only once every few minutes.
+const DefaultNamespaceWaitMaxInterval = 10 * time.Second
+
 type InformerAccess interface {
 	GetInternalKubeInformers() kinternalinformers.SharedInformerFactory
 	GetExternalKubeInformers() kinformers.SharedInformerFactory
@@ -480,7 +806,7 @@ func BuildOpenshiftControllerConfig(options configapi.MasterConfig, informers In
 		DockerImage:           imageTemplate.ExpandOrDie("docker-builder"),
 		STIImage:              imageTemplate.ExpandOrDie("sti-builder"),
 		AdmissionPluginConfig: options.AdmissionConfig.PluginConfig,
-		Codec: annotationCodec,
+		Codec:                 annotationCodec,
 	}
 
 	vars, err := getOpenShiftClientEnvVars(options)
@@ -1066,16 +1392,18 @@ func (c *MasterConfig) OAuthServerClients() (*osclient.Client, kclientsetinterna
 
 // ServiceAccountRoleBindingClient returns the client object used to bind roles to service accounts
 // It must have the following capabilities:
-//  get, list, update, create policyBindings and clusterPolicyBindings in all namespaces
+//
+//	get, list, update, create policyBindings and clusterPolicyBindings in all namespaces
 func (c *MasterConfig) ServiceAccountRoleBindingClient() *osclient.Client {
 	return c.PrivilegedLoopbackOpenShiftClient
 }
 
 // PolicyClient returns the policy client object
 // It must have the following capabilities:
-//  list, watch all policyBindings in all namespaces
-//  list, watch all policies in all namespaces
-//  create resourceAccessReviews in all namespaces
+//
+//	list, watch all policyBindings in all namespaces
+//	list, watch all policies in all namespaces
+//	create resourceAccessReviews in all namespaces
 func (c *MasterConfig) PolicyClient() *osclient.Client {
 	return c.PrivilegedLoopbackOpenShiftClient
 }
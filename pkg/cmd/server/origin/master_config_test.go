@@ -3,6 +3,11 @@ package origin
 import (
 	"reflect"
 	"testing"
+
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+
+	configapi "github.com/openshift/origin/pkg/cmd/server/api"
 )
 
 func TestQuotaAdmissionPluginsAreLast(t *testing.T) {
@@ -36,3 +41,56 @@ func TestFixupAdmissionPlugins(t *testing.T) {
 		t.Errorf("Expected: %v, but got: %v", expectedList, actualList)
 	}
 }
+
+// TestBootstrapClientConfigAppliesOverriddenQPSAndBurst verifies that bootstrapClientConfig copies base and
+// replaces only QPS/Burst with the configured overrides, leaving every other setting untouched.
+func TestBootstrapClientConfigAppliesOverriddenQPSAndBurst(t *testing.T) {
+	base := restclient.Config{Host: "https://example.com", QPS: 50, Burst: 100}
+	overrides := &configapi.ClientConnectionOverrides{QPS: 5, Burst: 10}
+
+	scoped := bootstrapClientConfig(base, overrides)
+
+	if scoped.QPS != 5 {
+		t.Errorf("expected QPS to be overridden to 5, got %v", scoped.QPS)
+	}
+	if scoped.Burst != 10 {
+		t.Errorf("expected Burst to be overridden to 10, got %v", scoped.Burst)
+	}
+	if scoped.Host != base.Host {
+		t.Errorf("expected Host to be left untouched, got %q", scoped.Host)
+	}
+}
+
+// TestBootstrapKubeClientReusesSharedClientWhenOverridesUnset verifies the documented default: bootstrap
+// reuses the existing loopback client unless ClientConnectionOverrides is explicitly configured.
+func TestBootstrapKubeClientReusesSharedClientWhenOverridesUnset(t *testing.T) {
+	sharedClient := fake.NewSimpleClientset()
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: sharedClient,
+	}
+
+	if got := c.bootstrapKubeClient(); got != sharedClient {
+		t.Errorf("expected bootstrapKubeClient to return the shared loopback client when overrides are unset")
+	}
+}
+
+// TestBootstrapKubeClientBuildsScopedClientWhenOverridesSet verifies that a configured
+// ClientConnectionOverrides causes bootstrap to build a dedicated client rather than reusing the shared one.
+func TestBootstrapKubeClientBuildsScopedClientWhenOverridesSet(t *testing.T) {
+	sharedClient := fake.NewSimpleClientset()
+	c := &MasterConfig{
+		PrivilegedLoopbackKubernetesClientsetInternal: sharedClient,
+		PrivilegedLoopbackClientConfig:                restclient.Config{Host: "https://example.com"},
+		Options: configapi.MasterConfig{
+			PolicyConfig: configapi.PolicyConfig{
+				Bootstrap: configapi.BootstrapOptions{
+					ClientConnectionOverrides: &configapi.ClientConnectionOverrides{QPS: 2, Burst: 4},
+				},
+			},
+		},
+	}
+
+	if got := c.bootstrapKubeClient(); got == sharedClient {
+		t.Errorf("expected bootstrapKubeClient to build a dedicated scoped client when overrides are set")
+	}
+}
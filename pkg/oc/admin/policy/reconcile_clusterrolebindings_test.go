@@ -3,10 +3,14 @@ package policy
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kapi "k8s.io/kubernetes/pkg/api"
 	kapihelper "k8s.io/kubernetes/pkg/api/helper"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/apis/authorization"
+	"github.com/openshift/origin/pkg/client/testclient"
+	"github.com/openshift/origin/pkg/cmd/server/bootstrappolicy"
 )
 
 func binding(roleRef kapi.ObjectReference, subjects []kapi.ObjectReference) *authorizationapi.ClusterRoleBinding {
@@ -190,3 +194,53 @@ func TestComputeUpdate(t *testing.T) {
 		}
 	}
 }
+
+// TestRunReconcileClusterRoleBindingsUnionPreservesAdminAddedDiscoverySubjects protects admins who've granted
+// additional subjects access to the system:discovery cluster role binding. Since discovery grants
+// unauthenticated/authenticated access to API discovery, an admin-added subject silently disappearing on the
+// next reconcile (for example on every master restart, since EnsureBootstrapPolicy always reconciles this
+// role) would be a serious regression. This exercises RunReconcileClusterRoleBindings end to end with
+// Union: true, the same mode EnsureBootstrapPolicy always uses for this binding, rather than only the
+// lower-level computeUpdatedBinding helper covered above.
+func TestRunReconcileClusterRoleBindingsUnionPreservesAdminAddedDiscoverySubjects(t *testing.T) {
+	adminAddedSubject := kapi.ObjectReference{Kind: authorizationapi.GroupKind, Name: "admin-added-group"}
+
+	existing := &authorizationapi.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: bootstrappolicy.DiscoveryRoleBindingName},
+		RoleRef:    kapi.ObjectReference{Name: bootstrappolicy.DiscoveryRoleName},
+		Subjects: []kapi.ObjectReference{
+			{Kind: authorizationapi.SystemGroupKind, Name: bootstrappolicy.AuthenticatedGroup},
+			{Kind: authorizationapi.SystemGroupKind, Name: bootstrappolicy.UnauthenticatedGroup},
+			adminAddedSubject,
+		},
+	}
+
+	fakeClient := testclient.NewSimpleFake(existing)
+	o := &ReconcileClusterRoleBindingsOptions{
+		RolesToReconcile:  []string{bootstrappolicy.DiscoveryRoleName},
+		Confirmed:         true,
+		Union:             true,
+		RoleBindingClient: fakeClient.ClusterRoleBindings(),
+	}
+
+	if err := o.RunReconcileClusterRoleBindings(nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reconciled, err := fakeClient.ClusterRoleBindings().Get(bootstrappolicy.DiscoveryRoleBindingName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error fetching the reconciled binding: %v", err)
+	}
+
+	subjects := sets.NewString()
+	for _, subject := range reconciled.Subjects {
+		subjects.Insert(subject.Name)
+	}
+
+	if !subjects.Has(adminAddedSubject.Name) {
+		t.Errorf("expected the admin-added subject %q to survive a Union reconcile, got %v", adminAddedSubject.Name, subjects.List())
+	}
+	if !subjects.Has(bootstrappolicy.AuthenticatedGroup) || !subjects.Has(bootstrappolicy.UnauthenticatedGroup) {
+		t.Errorf("expected the bootstrap subjects to remain present, got %v", subjects.List())
+	}
+}
@@ -229,13 +229,25 @@ func computeReconciledRole(expected authorizationapi.ClusterRole, actual authori
 	expectedAnnotationKeys := sets.StringKeySet(expected.Annotations)
 	missingAnnotationKeys := !existingAnnotationKeys.HasAll(expectedAnnotationKeys.List()...)
 
-	// Copy any existing labels, so the displayed update is correct
-	// This assumes bootstrap roles will not set any labels
-	// These labels aren't actually used during update; the latest labels are pulled from the existing object again
-	// Annotations are merged in a way that guarantees that user made changes have precedence over the defaults
-	// The latest annotations are pulled from the existing object again during update before doing the actual merge
-	expected.Labels = actual.Labels
-	expected.Annotations = mergeAnnotations(expected.Annotations, actual.Annotations)
+	// Some bootstrap roles carry labels (for example the RBAC aggregation selector labels an aggregated role's
+	// dependents match against) that a role reconcile must not clobber, and must also not let drift silently
+	// stick around on. Labels are merged with the opposite precedence from annotations: a bootstrap-defined
+	// label wins over a stale or hand-edited value on the same key, so an aggregated role can't silently lose
+	// or mismatch the label its dependents match against, while an admin's own annotations are still preserved.
+	// The latest labels/annotations are pulled from the existing object again during update before doing the
+	// actual merge.
+	existingLabelKeys := sets.StringKeySet(actual.Labels)
+	expectedLabelKeys := sets.StringKeySet(expected.Labels)
+	missingLabelKeys := !existingLabelKeys.HasAll(expectedLabelKeys.List()...)
+	for k, v := range expected.Labels {
+		if existingValue, ok := actual.Labels[k]; ok && existingValue != v {
+			missingLabelKeys = true
+			break
+		}
+	}
+
+	expected.Labels = mergeStringMaps(actual.Labels, expected.Labels)
+	expected.Annotations = mergeStringMaps(expected.Annotations, actual.Annotations)
 
 	_, extraRules := rulevalidation.Covers(expected.Rules, actual.Rules)
 	_, missingRules := rulevalidation.Covers(actual.Rules, expected.Rules)
@@ -244,7 +256,9 @@ func computeReconciledRole(expected authorizationapi.ClusterRole, actual authori
 	// 1. if we're missing rules
 	// 2. if there are extra rules we need to remove
 	// 3. if we are missing annotations
-	needsReconciliation := (len(missingRules) > 0) || (!union && len(extraRules) > 0) || missingAnnotationKeys
+	// 4. if we are missing labels, or a shared label key's value has drifted from the bootstrap definition
+	//    (for example an aggregation selector label added to, or hand-edited away from, the bootstrap definition)
+	needsReconciliation := (len(missingRules) > 0) || (!union && len(extraRules) > 0) || missingAnnotationKeys || missingLabelKeys
 
 	if !needsReconciliation {
 		return nil, false
@@ -278,7 +292,8 @@ func (o *ReconcileClusterRolesOptions) ReplaceChangedRoles(changedRoles []*autho
 		}
 
 		role.Rules = changedRoles[i].Rules
-		role.Annotations = mergeAnnotations(changedRoles[i].Annotations, role.Annotations)
+		role.Labels = mergeStringMaps(role.Labels, changedRoles[i].Labels)
+		role.Annotations = mergeStringMaps(changedRoles[i].Annotations, role.Annotations)
 		updatedRole, err := o.RoleClient.Update(role)
 		if err != nil {
 			errs = append(errs, err)
@@ -291,8 +306,9 @@ func (o *ReconcileClusterRolesOptions) ReplaceChangedRoles(changedRoles []*autho
 	return kerrors.NewAggregate(errs)
 }
 
-// mergeAnnotations combines the given annotation maps with the later annotations having higher precedence
-func mergeAnnotations(maps ...map[string]string) map[string]string {
+// mergeStringMaps combines the given label/annotation maps, with a key in a later map taking precedence over
+// the same key in an earlier one.
+func mergeStringMaps(maps ...map[string]string) map[string]string {
 	output := map[string]string{}
 	for _, m := range maps {
 		for k, v := range m {
@@ -94,23 +94,23 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("a"), ss{"2": "b"}, nil),
 			union:        false,
 
-			expectedReconciledRole:       nil,
-			expectedReconciliationNeeded: false,
+			expectedReconciledRole:       role(rules("a"), ss{"1": "a", "2": "b"}, nil),
+			expectedReconciliationNeeded: true,
 		},
 		"different labels with union": {
 			expectedRole: role(rules("a"), ss{"1": "a"}, nil),
 			actualRole:   role(rules("a"), ss{"2": "b"}, nil),
 			union:        true,
 
-			expectedReconciledRole:       nil,
-			expectedReconciliationNeeded: false,
+			expectedReconciledRole:       role(rules("a"), ss{"1": "a", "2": "b"}, nil),
+			expectedReconciliationNeeded: true,
 		},
 		"different labels and rules without union": {
 			expectedRole: role(rules("a"), ss{"1": "a"}, nil),
 			actualRole:   role(rules("b"), ss{"2": "b"}, nil),
 			union:        false,
 
-			expectedReconciledRole:       role(rules("a"), ss{"2": "b"}, nil),
+			expectedReconciledRole:       role(rules("a"), ss{"1": "a", "2": "b"}, nil),
 			expectedReconciliationNeeded: true,
 		},
 		"different labels and rules with union": {
@@ -118,7 +118,7 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("b"), ss{"2": "b"}, nil),
 			union:        true,
 
-			expectedReconciledRole:       role(rules("a", "b"), ss{"2": "b"}, nil),
+			expectedReconciledRole:       role(rules("a", "b"), ss{"1": "a", "2": "b"}, nil),
 			expectedReconciliationNeeded: true,
 		},
 		"conflicting labels and rules without union": {
@@ -126,7 +126,7 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("b"), ss{"1": "b"}, nil),
 			union:        false,
 
-			expectedReconciledRole:       role(rules("a"), ss{"1": "b"}, nil),
+			expectedReconciledRole:       role(rules("a"), ss{"1": "a"}, nil),
 			expectedReconciliationNeeded: true,
 		},
 		"conflicting labels and rules with union": {
@@ -134,7 +134,15 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("b"), ss{"1": "b"}, nil),
 			union:        true,
 
-			expectedReconciledRole:       role(rules("a", "b"), ss{"1": "b"}, nil),
+			expectedReconciledRole:       role(rules("a", "b"), ss{"1": "a"}, nil),
+			expectedReconciliationNeeded: true,
+		},
+		"same label key with drifted value and matching rules": {
+			expectedRole: role(rules("a"), ss{"1": "a"}, nil),
+			actualRole:   role(rules("a"), ss{"1": "b"}, nil),
+			union:        false,
+
+			expectedReconciledRole:       role(rules("a"), ss{"1": "a"}, nil),
 			expectedReconciliationNeeded: true,
 		},
 		"match annotations without union": {
@@ -206,7 +214,7 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("b"), ss{"4": "e"}, ss{"1": "b"}),
 			union:        false,
 
-			expectedReconciledRole:       role(rules("a"), ss{"4": "e"}, ss{"1": "b"}),
+			expectedReconciledRole:       role(rules("a"), ss{"3": "d", "4": "e"}, ss{"1": "b"}),
 			expectedReconciliationNeeded: true,
 		},
 		"conflicting labels/annotations and rules with union": {
@@ -214,7 +222,7 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("b"), ss{"4": "e"}, ss{"1": "b"}),
 			union:        true,
 
-			expectedReconciledRole:       role(rules("a", "b"), ss{"4": "e"}, ss{"1": "b"}),
+			expectedReconciledRole:       role(rules("a", "b"), ss{"3": "d", "4": "e"}, ss{"1": "b"}),
 			expectedReconciliationNeeded: true,
 		},
 		"complex labels/annotations and rules without union": {
@@ -222,7 +230,7 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("nodes", "images", "projects"), ss{"color": "red", "team": "pm"}, ss{"system": "false", "owner": "admin", "vip": "yes"}),
 			union:        false,
 
-			expectedReconciledRole:       role(rules("pods", "nodes", "secrets"), ss{"color": "red", "team": "pm"}, ss{"description": "fancy", "system": "false", "owner": "admin", "vip": "yes"}),
+			expectedReconciledRole:       role(rules("pods", "nodes", "secrets"), ss{"env": "prod", "color": "blue", "team": "pm"}, ss{"description": "fancy", "system": "false", "owner": "admin", "vip": "yes"}),
 			expectedReconciliationNeeded: true,
 		},
 		"complex labels/annotations and rules with union": {
@@ -230,7 +238,23 @@ func TestComputeReconciledRole(t *testing.T) {
 			actualRole:   role(rules("nodes", "images", "projects"), ss{"color": "red", "team": "pm"}, ss{"system": "false", "owner": "admin", "vip": "yes", "rate": "down"}),
 			union:        true,
 
-			expectedReconciledRole:       role(rules("pods", "nodes", "secrets", "images", "projects"), ss{"color": "red", "team": "pm"}, ss{"description": "fancy", "system": "false", "owner": "admin", "vip": "yes", "rate": "down", "up": "true"}),
+			expectedReconciledRole:       role(rules("pods", "nodes", "secrets", "images", "projects"), ss{"env": "prod", "color": "blue", "team": "pm", "manager": "randy"}, ss{"description": "fancy", "system": "false", "owner": "admin", "vip": "yes", "rate": "down", "up": "true"}),
+			expectedReconciliationNeeded: true,
+		},
+		"aggregated role gains its bootstrap aggregation label": {
+			expectedRole: role(rules("pods"), ss{"rbac.authorization.k8s.io/aggregate-to-admin": "true"}, nil),
+			actualRole:   role(rules("pods"), nil, nil),
+			union:        true,
+
+			expectedReconciledRole:       role(rules("pods"), ss{"rbac.authorization.k8s.io/aggregate-to-admin": "true"}, nil),
+			expectedReconciliationNeeded: true,
+		},
+		"aggregated role keeps its bootstrap aggregation label alongside an admin-added one": {
+			expectedRole: role(rules("pods"), ss{"rbac.authorization.k8s.io/aggregate-to-admin": "true"}, nil),
+			actualRole:   role(rules("pods"), ss{"team": "pm"}, nil),
+			union:        true,
+
+			expectedReconciledRole:       role(rules("pods"), ss{"rbac.authorization.k8s.io/aggregate-to-admin": "true", "team": "pm"}, nil),
 			expectedReconciliationNeeded: true,
 		},
 	}